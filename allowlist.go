@@ -0,0 +1,70 @@
+package mailcop
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrDomainNotAllowed is returned in ValidationResult.LastError when AllowedDomainPatterns
+// is set and the domain doesn't match any of the patterns.
+var ErrDomainNotAllowed = errors.New("domain not allowed")
+
+// compilePatterns compiles domain or address patterns. Patterns enclosed in slashes,
+// like "/^mail[0-9]+\.acme\.com$/", are case-insensitive regular expressions. Anything
+// else is a glob where "*" matches any sequence of characters and "?" matches one, so
+// "*.acme.com" matches every subdomain of acme.com but not acme.com itself.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// compilePattern compiles a single glob or /regex/ pattern
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	expr := globToRegexp(strings.ToLower(pattern))
+	if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		expr = "(?i)" + pattern[1:len(pattern)-1]
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+	}
+	return re, nil
+}
+
+// globToRegexp converts a glob into an anchored regular expression
+func globToRegexp(glob string) string {
+	expr := regexp.QuoteMeta(glob)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return "^" + expr + "$"
+}
+
+// isAllowedDomain checks if a domain matches at least one allowed domain pattern.
+// Every domain is allowed when no patterns are configured.
+func (v *Validator) isAllowedDomain(domain string) bool {
+	if len(v.allowedDomains) == 0 {
+		return true
+	}
+
+	for _, re := range v.allowedDomains {
+		if re.MatchString(domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package mailcop_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestAllowedDomainPatterns(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.AllowedDomainPatterns = []string{
+		"acme.com",
+		"*.acme.com",
+		"partner-?.org",
+		`/^mail[0-9]+\.example\.net$/`,
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		email     string
+		wantValid bool
+	}{
+		{email: "user@acme.com", wantValid: true},
+		{email: "user@ACME.com", wantValid: true},
+		{email: "user@sales.acme.com", wantValid: true},
+		{email: "user@eu.sales.acme.com", wantValid: true},
+		{email: "user@partner-a.org", wantValid: true},
+		{email: "user@mail42.example.net", wantValid: true},
+		{email: "user@notacme.com", wantValid: false},
+		{email: "user@acme.com.evil.org", wantValid: false},
+		{email: "user@partner-ab.org", wantValid: false},
+		{email: "user@mail.example.net", wantValid: false},
+		{email: "user@gmail.com", wantValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantValid, result.IsValid)
+			if !tt.wantValid {
+				assert.ErrorIs(t, result.LastError, mailcop.ErrDomainNotAllowed)
+			}
+		})
+	}
+
+	t.Run("invalid regex", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.AllowedDomainPatterns = []string{"/[/"}
+
+		_, err := mailcop.New(opts)
+		assert.Error(t, err)
+	})
+
+	t.Run("no patterns allows every domain", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.DefaultOptions())
+		require.NoError(t, err)
+		assert.True(t, v.Validate("user@gmail.com").IsValid)
+	})
+}
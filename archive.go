@@ -0,0 +1,156 @@
+package mailcop
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// gzipMagic and zipMagic are the leading bytes of gzip files and zip archives
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+)
+
+// maxListSize is the largest list that's read, after decompression, so a small gzip
+// or zip "bomb" from a remote URL can't exhaust memory. Zip archives are limited in
+// total, across their files.
+var maxListSize int64 = 64 << 20
+
+// errListTooLarge is returned when a list exceeds maxListSize
+var errListTooLarge = errors.New("list exceeds the maximum size")
+
+// readList reads a list of at most limit bytes, failing once the limit is exceeded
+func readList(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w of %d bytes", errListTooLarge, maxListSize)
+	}
+	return data, nil
+}
+
+// gunzipList decompresses a gzip-compressed list. Other data is returned unchanged.
+func gunzipList(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip data: %v", err)
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	data, err = readList(reader, maxListSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip data: %v", err)
+	}
+	return data, nil
+}
+
+// isZipList reports whether a list is a zip archive, either because its URL ends in
+// ".zip" or because of its contents
+func isZipList(urlStr string, data []byte) bool {
+	if parsed, err := url.Parse(urlStr); err == nil && strings.EqualFold(path.Ext(parsed.Path), ".zip") {
+		return true
+	}
+	return bytes.HasPrefix(data, zipMagic)
+}
+
+// isTextList reports whether a list holds one entry per line rather than JSON, either
+// because its URL ends in ".txt" or ".conf" (optionally followed by ".gz") or because
+// it starts with a "#" or "//" comment
+func isTextList(urlStr string, data []byte) bool {
+	if parsed, err := url.Parse(urlStr); err == nil {
+		ext := path.Ext(strings.TrimSuffix(strings.ToLower(parsed.Path), ".gz"))
+		if ext == ".txt" || ext == ".conf" {
+			return true
+		}
+	}
+	data = bytes.TrimSpace(data)
+	return bytes.HasPrefix(data, []byte("#")) || bytes.HasPrefix(data, []byte("//"))
+}
+
+// parseZipList merges the lists in a zip archive. JSON files are parsed like any other
+// list, and .txt and .conf files are parsed as text lists (see parseTextList). Other
+// files, such as a README or LICENSE, are skipped.
+func parseZipList(data []byte) ([]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %v", err)
+	}
+
+	var providers []string
+	var lists int
+	remaining := maxListSize
+	for _, file := range archive.File {
+		ext := strings.ToLower(path.Ext(file.Name))
+		if file.FileInfo().IsDir() || (ext != ".json" && ext != ".txt" && ext != ".conf") {
+			continue
+		}
+
+		contents, err := readZipFile(file, remaining)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from zip archive: %v", file.Name, err)
+		}
+		remaining -= int64(len(contents))
+
+		var entries []string
+		if ext == ".json" {
+			entries, err = parseProviderList(contents)
+		} else {
+			entries, err = parseTextList(contents)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s in zip archive: %v", file.Name, err)
+		}
+
+		providers = append(providers, entries...)
+		lists++
+	}
+
+	if lists == 0 {
+		return nil, fmt.Errorf("zip archive contains no .json, .txt or .conf lists")
+	}
+	return providers, nil
+}
+
+// readZipFile reads the decompressed contents of a file in a zip archive, up to limit bytes
+func readZipFile(file *zip.File, limit int64) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	return readList(reader, limit)
+}
+
+// parseTextList parses a list with one entry per line, ignoring blank lines and lines
+// starting with "#" or "//" comments
+func parseTextList(data []byte) ([]string, error) {
+	var entries []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,74 @@
+package mailcop
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxListSize(t *testing.T) {
+	defer func(size int64) {
+		maxListSize = size
+	}(maxListSize)
+	maxListSize = 1024
+
+	// Each list decompresses to a little over the limit from far fewer bytes
+	large := strings.Repeat("a", 1025)
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, err := w.Write([]byte(large))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	newZip := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		for name, contents := range files {
+			f, err := w.Create(name)
+			require.NoError(t, err)
+			_, err = f.Write([]byte(contents))
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		_, err := gunzipList(gz.Bytes())
+		assert.ErrorContains(t, err, "list exceeds the maximum size of 1024 bytes")
+	})
+
+	t.Run("zip file", func(t *testing.T) {
+		_, err := parseZipList(newZip(map[string]string{"large.txt": large}))
+		assert.ErrorContains(t, err, "list exceeds the maximum size")
+	})
+
+	t.Run("zip total", func(t *testing.T) {
+		half := strings.Repeat("a", 600)
+		_, err := parseZipList(newZip(map[string]string{"a.txt": half, "b.txt": half}))
+		assert.ErrorContains(t, err, "list exceeds the maximum size")
+
+		entries, err := parseZipList(newZip(map[string]string{"a.txt": half}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{half}, entries)
+	})
+
+	t.Run("remote", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(large))
+		}))
+		defer server.Close()
+
+		_, _, retry, err := fetchList(server.URL)
+		assert.ErrorIs(t, err, errListTooLarge)
+		assert.False(t, retry, "a list that's too large isn't retried")
+	})
+}
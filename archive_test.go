@@ -0,0 +1,214 @@
+package mailcop_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+// zipArchive builds a zip archive holding files keyed by name
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, contents := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestArchiveLists(t *testing.T) {
+	archive := zipArchive(t, map[string]string{
+		"lists/domains.txt":  "# Disposable domains\nmailinator.com\n\n  yopmail.com  \n",
+		"lists/extra.json":   `["guerrillamail.com"]`,
+		"lists/blocked.conf": "sharklasers.com\n",
+		"README.md":          "not a list",
+	})
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, err := gz.Write([]byte(`["mailinator.com", "yopmail.com", "guerrillamail.com", "sharklasers.com"]`))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/release.zip":
+			_, _ = w.Write(archive)
+		case "/broken.zip":
+			_, _ = w.Write([]byte(`["mailinator.com"]`))
+		case "/domains.json.gz":
+			_, _ = w.Write(gzipped.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "release.zip")
+	require.NoError(t, os.WriteFile(archivePath, archive, 0644))
+
+	for name, url := range map[string]string{
+		"zip over HTTP":  server.URL + "/release.zip",
+		"zip file":       "file://" + archivePath,
+		"gzip over HTTP": server.URL + "/domains.json.gz",
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts := mailcop.DefaultOptions()
+			opts.CheckDisposable = true
+			opts.DisposableDomainsURL = url
+
+			v, err := mailcop.New(opts)
+			require.NoError(t, err)
+
+			for _, domain := range []string{"mailinator.com", "yopmail.com", "guerrillamail.com", "sharklasers.com"} {
+				assert.True(t, v.IsDisposableDomain(domain), domain)
+			}
+			assert.False(t, v.IsDisposableDomain("company.org"))
+		})
+	}
+
+	t.Run("malformed archives", func(t *testing.T) {
+		invalidJSON := zipArchive(t, map[string]string{"domains.json": `{"domains": [`})
+		invalidPath := filepath.Join(dir, "invalid.zip")
+		require.NoError(t, os.WriteFile(invalidPath, invalidJSON, 0644))
+
+		noLists := zipArchive(t, map[string]string{"LICENSE": "MIT"})
+		noListsPath := filepath.Join(dir, "empty.zip")
+		require.NoError(t, os.WriteFile(noListsPath, noLists, 0644))
+
+		tests := map[string]struct {
+			url     string
+			wantErr string
+		}{
+			"not a zip":    {url: server.URL + "/broken.zip", wantErr: "invalid zip archive"},
+			"invalid JSON": {url: "file://" + invalidPath, wantErr: "domains.json"},
+			"no lists":     {url: "file://" + noListsPath, wantErr: "no .json, .txt or .conf lists"},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				opts := mailcop.DefaultOptions()
+				opts.CheckDisposable = true
+				opts.DisposableDomainsURL = tt.url
+
+				_, err := mailcop.New(opts)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestTextLists(t *testing.T) {
+	commented, err := os.ReadFile(filepath.Join("testdata", "commented.txt"))
+	require.NoError(t, err)
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, err = gz.Write(commented)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blocklist":
+			_, _ = w.Write(commented)
+		case "/blocklist.conf":
+			_, _ = w.Write([]byte("mailinator.com\nyopmail.com\nguerrillamail.com\nsharklasers.com\n"))
+		case "/blocklist.txt.gz":
+			_, _ = w.Write(gzipped.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	for name, url := range map[string]string{
+		"commented file":         "file://" + filepath.Join("testdata", "commented.txt"),
+		"commented without ext":  server.URL + "/blocklist",
+		"uncommented conf":       server.URL + "/blocklist.conf",
+		"gzipped text over HTTP": server.URL + "/blocklist.txt.gz",
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts := mailcop.DefaultOptions()
+			opts.CheckDisposable = true
+			opts.DisposableDomainsURL = url
+
+			v, err := mailcop.New(opts)
+			require.NoError(t, err)
+
+			domains, ok := v.DisposableDomains()
+			require.True(t, ok)
+			assert.ElementsMatch(t, []string{"mailinator.com", "yopmail.com", "guerrillamail.com", "sharklasers.com"}, domains)
+		})
+	}
+}
+
+func TestFreeProviderListFormats(t *testing.T) {
+	gzipBytes := func(data string) string {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		return buf.String()
+	}
+
+	lists := map[string]string{
+		"/array.json":    `["freeone.net", "freetwo.net"]`,
+		"/domains.json":  `{"domains": ["freeone.net", "freetwo.net"]}`,
+		"/index.json":    `{"freeone.net": {}, "freetwo.net": {}}`,
+		"/array.json.gz": gzipBytes(`["freeone.net", "freetwo.net"]`),
+		"/list.txt":      "# Free providers\nfreeone.net\n\n// More\nfreetwo.net\n",
+		"/list.conf":     "freeone.net\nfreetwo.net\n",
+		"/list.txt.gz":   gzipBytes("freeone.net\nfreetwo.net\n"),
+		"/commented":     "# No extension\nfreeone.net\nfreetwo.net\n",
+		"/messy.json":    `[" FreeOne.NET. ", "mailto:freetwo.net", ""]`,
+		"/release.zip":   string(zipArchive(t, map[string]string{"a.txt": "freeone.net\n", "b.json": `["freetwo.net"]`})),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list, ok := lists[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(list))
+	}))
+	defer server.Close()
+
+	for path := range lists {
+		t.Run(path, func(t *testing.T) {
+			opts := mailcop.DefaultOptions()
+			opts.CheckDisposable = true
+			opts.DisposableDomainsURL = server.URL + path
+			opts.CheckFreeProvider = true
+			opts.FreeProvidersURL = server.URL + path
+
+			v, err := mailcop.New(opts)
+			require.NoError(t, err)
+
+			for _, domain := range []string{"freeone.net", "freetwo.net"} {
+				assert.True(t, v.IsDisposableDomain(domain), "disposable %s", domain)
+				assert.True(t, v.Validate("user@"+domain).IsFreeProvider, "free provider %s", domain)
+			}
+			assert.False(t, v.Validate("user@company.org").IsFreeProvider)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package mailcop
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrBannedLocalPart is returned in ValidationResult.LastError when the local part is
+// on the banned list
+var ErrBannedLocalPart = errors.New("local part is not allowed")
+
+// RegisterBannedLocalParts bans local parts outright (e.g. "test" or "fake"). Matching
+// is case-insensitive, and a "+tag" subaddress is ignored, so banning "test" also
+// rejects "Test+1@example.com".
+func (v *Validator) RegisterBannedLocalParts(localParts []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.bannedLocalParts == nil {
+		v.bannedLocalParts = make(map[string]struct{}, len(localParts))
+	}
+	for _, localPart := range localParts {
+		localPart = strings.ToLower(strings.TrimSpace(localPart))
+		if localPart != "" {
+			v.bannedLocalParts[localPart] = struct{}{}
+		}
+	}
+}
+
+// LoadBannedLocalParts loads banned local parts from a JSON array in a file or URL
+func (v *Validator) LoadBannedLocalParts(urlStr string) error {
+	if urlStr == "" {
+		return nil
+	}
+
+	localParts, _, err := v.loadProviderList(urlStr)
+	if err != nil {
+		return fmt.Errorf("failed to load banned local parts: %v", err)
+	}
+
+	v.RegisterBannedLocalParts(localParts)
+
+	v.logger.Debug("list refreshed", "list", "banned_local_parts", "url", urlStr, "count", len(localParts))
+	return nil
+}
+
+// isBannedLocalPart checks if a local part, with or without its subaddress, is banned
+func (v *Validator) isBannedLocalPart(localPart string) bool {
+	localPart = strings.ToLower(localPart)
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if _, ok := v.bannedLocalParts[localPart]; ok {
+		return true
+	}
+	if base, _, ok := strings.Cut(localPart, "+"); ok {
+		_, ok = v.bannedLocalParts[base]
+		return ok
+	}
+	return false
+}
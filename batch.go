@@ -0,0 +1,110 @@
+package mailcop
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ValidateFile reads newline-delimited email addresses from a file and validates
+// them concurrently. Empty lines and lines starting with "#" are skipped. When
+// dedup is true, repeated addresses are only validated once, compared as with
+// DedupInput, so "user@Example.com" repeats "user@example.com".
+func (v *Validator) ValidateFile(path string, dedup bool) ([]ValidationResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	var emails []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		emails = append(emails, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	if dedup {
+		emails, _ = dedupEmails(emails)
+	}
+
+	return v.ValidateMany(emails), nil
+}
+
+// resultFlag is a boolean field of ValidationResult written as a CSV column
+type resultFlag struct {
+	column string
+	value  func(ValidationResult) bool
+}
+
+// resultFlags lists every boolean field of ValidationResult, in the order of their
+// CSV columns. New fields are added at the end so existing columns keep their place.
+var resultFlags = []resultFlag{
+	{"is_valid", func(r ValidationResult) bool { return r.IsValid }},
+	{"is_disposable", func(r ValidationResult) bool { return r.IsDisposable }},
+	{"is_free_provider", func(r ValidationResult) bool { return r.IsFreeProvider }},
+	{"is_ip_domain", func(r ValidationResult) bool { return r.IsIPDomain }},
+	{"is_reserved", func(r ValidationResult) bool { return r.IsReserved }},
+	{"is_valid_tld", func(r ValidationResult) bool { return r.IsValidTLD }},
+	{"has_mx", func(r ValidationResult) bool { return r.HasMX }},
+	{"can_receive_mail", func(r ValidationResult) bool { return r.CanReceiveMail }},
+	{"is_dnssec_validated", func(r ValidationResult) bool { return r.IsDNSSECValidated }},
+	{"has_dmarc", func(r ValidationResult) bool { return r.HasDMARC }},
+	{"has_spf", func(r ValidationResult) bool { return r.HasSPF }},
+	{"dns_inconclusive", func(r ValidationResult) bool { return r.DNSInconclusive }},
+	{"dns_cache_hit", func(r ValidationResult) bool { return r.DNSCacheHit }},
+	{"display_name_spoof", func(r ValidationResult) bool { return r.DisplayNameSpoof }},
+	{"is_confusable", func(r ValidationResult) bool { return r.IsConfusable }},
+	{"is_duplicate", func(r ValidationResult) bool { return r.IsDuplicate }},
+	{"is_public_suffix", func(r ValidationResult) bool { return r.IsPublicSuffix }},
+	{"is_spamtrap", func(r ValidationResult) bool { return r.IsSpamtrap }},
+	{"is_utf8_address", func(r ValidationResult) bool { return r.IsUTF8Address }},
+	{"resolves_to_private_ip", func(r ValidationResult) bool { return r.ResolvesToPrivateIP }},
+}
+
+// WriteResultsCSV writes validation results to w as CSV, with a header row
+// followed by one row per result. Each row has the original input, address and
+// name, a true/false column for every boolean flag of ValidationResult, and the
+// error message. Fields are quoted as needed, so names and error messages
+// containing commas or quotes are escaped correctly.
+func WriteResultsCSV(w io.Writer, results []ValidationResult) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"original", "address", "name"}
+	for _, flag := range resultFlags {
+		header = append(header, flag.column)
+	}
+	header = append(header, "error")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		record := make([]string, 0, len(header))
+		record = append(record, result.Original, result.Address, result.Name)
+		for _, flag := range resultFlags {
+			record = append(record, strconv.FormatBool(flag.value(result)))
+		}
+		record = append(record, result.ErrorMessage())
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,156 @@
+package mailcop_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestValidateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "emails.txt")
+	data := `# exported list
+user@example.com
+
+  user@example.com
+user@Example.COM
+invalid@
+# another comment
+other@example.org
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	v, err := mailcop.New(mailcop.DefaultOptions())
+	require.NoError(t, err)
+
+	t.Run("without dedup", func(t *testing.T) {
+		results, err := v.ValidateFile(path, false)
+		require.NoError(t, err)
+		assert.Len(t, results, 5)
+	})
+
+	t.Run("with dedup", func(t *testing.T) {
+		results, err := v.ValidateFile(path, true)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+
+		valid := 0
+		for _, result := range results {
+			if result.IsValid {
+				valid++
+			}
+		}
+		assert.Equal(t, 2, valid)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := v.ValidateFile(filepath.Join(t.TempDir(), "missing.txt"), false)
+		assert.Error(t, err)
+	})
+}
+
+func TestWriteResultsCSV(t *testing.T) {
+	results := []mailcop.ValidationResult{
+		{
+			Original: `"Doe, John" <john@example.com>`,
+			Address:  "john@example.com",
+			Name:     "Doe, John",
+			IsValid:  true,
+		},
+		{
+			Original:     "user@tempmail.com",
+			Address:      "user@tempmail.com",
+			IsDisposable: true,
+			LastError:    errors.New(`disposable domain: "tempmail.com", rejected`),
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, mailcop.WriteResultsCSV(&buf, results))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, "original", records[0][0])
+	assert.Equal(t, "error", records[0][len(records[0])-1])
+
+	assert.Equal(t, `"Doe, John" <john@example.com>`, records[1][0])
+	assert.Equal(t, "Doe, John", records[1][2])
+	assert.Equal(t, "true", records[1][3])
+	assert.Equal(t, "", records[1][len(records[1])-1])
+
+	assert.Equal(t, "false", records[2][3])
+	assert.Equal(t, "true", records[2][4])
+	assert.Equal(t, `disposable domain: "tempmail.com", rejected`, records[2][len(records[2])-1])
+
+	t.Run("every boolean flag has a column", func(t *testing.T) {
+		var result mailcop.ValidationResult
+		flags := 0
+		rv := reflect.ValueOf(&result).Elem()
+		for i := 0; i < rv.NumField(); i++ {
+			if rv.Field(i).Kind() == reflect.Bool {
+				rv.Field(i).SetBool(true)
+				flags++
+			}
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, mailcop.WriteResultsCSV(&buf, []mailcop.ValidationResult{result}))
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records[1], flags+4)
+		for i, value := range records[1][3 : 3+flags] {
+			assert.Equal(t, "true", value, records[0][3+i])
+		}
+	})
+}
+
+func TestSummarize(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.CheckFreeProvider = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+	opts.RejectDisposable = true
+	opts.RejectIPDomains = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	results := v.ValidateMany([]string{
+		"user@company.org",
+		"user@gmail.com",
+		"user@example.com",
+		"user@tempmail.com",
+		"other@throwaway.com",
+		"user@[192.168.1.1]",
+		"invalid@",
+	})
+
+	summary := mailcop.Summarize(results)
+	assert.Equal(t, 7, summary.Total)
+	assert.Equal(t, 3, summary.Valid)
+	assert.Equal(t, 4, summary.Invalid)
+	assert.Equal(t, 2, summary.Disposable)
+	assert.Equal(t, 1, summary.FreeProvider)
+	assert.Equal(t, 1, summary.Reserved)
+	assert.Equal(t, 1, summary.IPDomain)
+	assert.Equal(t, map[mailcop.Reason]int{
+		mailcop.ReasonDisposable: 2,
+		mailcop.ReasonIPDomain:   1,
+		mailcop.ReasonSyntax:     1,
+	}, summary.Reasons)
+
+	empty := mailcop.Summarize(nil)
+	assert.Zero(t, empty.Total)
+	assert.Empty(t, empty.Reasons)
+}
@@ -1,8 +1,11 @@
 package mailcop
 
 import (
+	"bufio"
 	"fmt"
 	"io"
+	"strconv"
+	"time"
 
 	"github.com/bits-and-blooms/bloom/v3"
 )
@@ -14,17 +17,20 @@ type BloomOptions struct {
 	FalsePositiveRate float64
 
 	// VerificationAttempts reduces false positives exponentially by checking
-	// the domain multiple times with different hash functions. Each check must
-	// return "probably in set" for the domain to be considered disposable.
-	// The actual false positive rate becomes FalsePositiveRate^VerificationAttempts.
+	// the domain against multiple independent filters. The first filter stores
+	// the domain as-is and each additional filter stores the domain with a
+	// per-attempt salt appended, so every filter hashes the domain differently.
+	// Each check must return "probably in set" for the domain to be considered
+	// disposable. The actual false positive rate becomes
+	// FalsePositiveRate^VerificationAttempts.
 	//
 	// Examples with FalsePositiveRate = 0.01 (1%):
 	// - 1 attempt: 1% false positives (0.01^1)
 	// - 2 attempts: 0.01% false positives (0.01^2)
 	// - 3 attempts: 0.0001% false positives (0.01^3)
 	//
-	// Higher values provide better accuracy at the cost of more CPU time.
-	// Default is 1.
+	// Each attempt maintains its own filter, so memory usage grows linearly
+	// with the number of attempts. Default is 1.
 	VerificationAttempts int
 }
 
@@ -38,41 +44,90 @@ func DefaultBloomOptions() BloomOptions {
 
 // UseBloomFilter converts the validator to use a bloom filter instead of a map
 // for disposable domain checking. This can significantly reduce memory usage.
-// The expectedItems parameter should be set to the approximate number of
-// disposable domains you expect to add to the filter.
+// The domains found at the given URL are added to the filter along with those already
+// loaded, and the filter is sized for both. The list is loaded before the validator is
+// locked, so validations carry on while it's fetched.
 func (v *Validator) UseBloomFilter(url string, opts BloomOptions) error {
-	v.mu.Lock()
-	defer v.mu.Unlock()
-
 	if url == "" {
 		return fmt.Errorf("URL is required")
 	}
+	return v.UseBloomFilterFromSources([]string{url}, 0, opts)
+}
 
-	// Load the list of disposable domains
-	domains, err := v.loadProviderList(url)
-	if err != nil {
-		return fmt.Errorf("failed to load provider list: %v", err)
+// UseBloomFilterFromSources is like UseBloomFilter, but combines the disposable domains
+// from several lists into one filter. The filter is sized for expectedTotal domains, or
+// for the number found if that's more, so combining lists doesn't push the false
+// positive rate above FalsePositiveRate as adding them to a filter sized for one list would.
+func (v *Validator) UseBloomFilterFromSources(urls []string, expectedTotal uint, opts BloomOptions) error {
+	if len(urls) == 0 {
+		return fmt.Errorf("at least one URL is required")
 	}
 
-	// Create new bloom filter with given parameters
-	filter := bloom.NewWithEstimates(uint(len(domains)), opts.FalsePositiveRate)
+	// Load every list before switching, so a failed source leaves the validator as it was
+	var domains []string
+	var updated time.Time
+	for _, url := range urls {
+		list, listUpdated, err := v.loadDomainList(url)
+		if err != nil {
+			return fmt.Errorf("failed to load provider list %s: %v", url, err)
+		}
+		domains = append(domains, list...)
+		if listUpdated.After(updated) {
+			updated = listUpdated
+		}
+	}
 
-	// If we have existing domains, add them to the bloom filter
-	for domain := range v.disposableDomains {
-		filter.Add([]byte(domain))
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	defer v.invalidateVerdicts()
+
+	size := uint(len(domains) + len(v.disposableDomains))
+	v.switchToBloomFilter(max(expectedTotal, size), opts, updated)
+	for _, domain := range domains {
+		v.addToBloomFilter(domain)
+	}
+
+	v.logger.Debug("list refreshed", "list", "disposable", "sources", len(urls), "count", len(domains), "capacity", max(expectedTotal, size))
+	return nil
+}
+
+// switchToBloomFilter replaces the disposable domain map with bloom filters sized for n
+// domains, moving any domains already loaded into them. The caller must hold the write lock.
+func (v *Validator) switchToBloomFilter(n uint, opts BloomOptions, updated time.Time) {
+	if opts.VerificationAttempts < 1 {
+		opts.VerificationAttempts = 1
+	}
+
+	// Create one filter per verification attempt with the given parameters
+	filter := bloom.NewWithEstimates(n, opts.FalsePositiveRate)
+	salted := make([]*bloom.BloomFilter, opts.VerificationAttempts-1)
+	for i := range salted {
+		salted[i] = bloom.NewWithEstimates(n, opts.FalsePositiveRate)
 	}
 
 	// Switch to bloom filter implementation
 	v.bloomFilter = filter
+	v.bloomSalted = salted
+	v.bloomOptions = opts
+	v.disposableUpdated = updated
 
-	// Clear the existing map
-	v.disposableDomains = make(map[string]struct{})
+	// If we have existing domains, add them to the bloom filter
+	for domain := range v.disposableDomains {
+		v.addToBloomFilter(domain)
+	}
 
-	v.bloomOptions = opts
-	return nil
+	// Clear the existing map and its sources
+	v.disposableDomains = make(map[string]struct{})
+	v.disposableSources = make(map[string]string)
+	v.disposableAlsoIn = make(map[string][]string)
+	v.disposableLists = make(map[string]map[string]struct{})
+	if v.disposableTrie != nil {
+		v.disposableTrie = newSuffixTrie()
+	}
 }
 
-// SaveBloomFilter serializes the bloom filter to the provided writer
+// SaveBloomFilter serializes the bloom filter to the provided writer. When
+// multiple verification attempts are configured, every filter is written in order.
 func (v *Validator) SaveBloomFilter(w io.Writer) error {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
@@ -81,20 +136,73 @@ func (v *Validator) SaveBloomFilter(w io.Writer) error {
 		return fmt.Errorf("bloom filter not initialized")
 	}
 
-	_, err := v.bloomFilter.WriteTo(w)
-	return err
+	if _, err := v.bloomFilter.WriteTo(w); err != nil {
+		return err
+	}
+
+	for _, filter := range v.bloomSalted {
+		if _, err := filter.WriteTo(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-// LoadBloomFilter deserializes the bloom filter from the provided reader
+// LoadBloomFilter deserializes the bloom filter from the provided reader. All
+// filters written by SaveBloomFilter are read, and VerificationAttempts is set
+// to match the number of filters found.
 func (v *Validator) LoadBloomFilter(r io.Reader) error {
 	v.mu.Lock()
 	defer v.mu.Unlock()
+	defer v.invalidateVerdicts()
 
-	filter := &bloom.BloomFilter{}
-	if _, err := filter.ReadFrom(r); err != nil {
-		return err
+	br := bufio.NewReader(r)
+
+	var filters []*bloom.BloomFilter
+	for {
+		if _, err := br.Peek(1); err == io.EOF && len(filters) > 0 {
+			break
+		}
+
+		filter := &bloom.BloomFilter{}
+		if _, err := filter.ReadFrom(br); err != nil {
+			return err
+		}
+		filters = append(filters, filter)
 	}
 
-	v.bloomFilter = filter
+	v.bloomFilter = filters[0]
+	v.bloomSalted = filters[1:]
+	v.bloomOptions.VerificationAttempts = len(filters)
 	return nil
 }
+
+// addToBloomFilter adds a domain to the primary filter and every salted filter.
+// The caller must hold the write lock.
+func (v *Validator) addToBloomFilter(domain string) {
+	domain = normalizeDomain(domain)
+	v.bloomFilter.Add([]byte(domain))
+	for i, filter := range v.bloomSalted {
+		filter.Add(saltedKey(domain, i+1))
+	}
+}
+
+// testBloomFilter reports whether a domain is probably in every filter.
+// The caller must hold at least the read lock.
+func (v *Validator) testBloomFilter(domain string) bool {
+	if !v.bloomFilter.Test([]byte(domain)) {
+		return false
+	}
+	for i, filter := range v.bloomSalted {
+		if !filter.Test(saltedKey(domain, i+1)) {
+			return false
+		}
+	}
+	return true
+}
+
+// saltedKey returns the domain bytes with a per-attempt salt appended
+func saltedKey(domain string, attempt int) []byte {
+	return []byte(domain + "#" + strconv.Itoa(attempt))
+}
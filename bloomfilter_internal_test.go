@@ -0,0 +1,116 @@
+package mailcop
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bits-and-blooms/bloom/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomFilterVerificationAttempts(t *testing.T) {
+	testDataPath := "file://" + filepath.Join("testdata", "domains.json")
+
+	opts := DefaultOptions()
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = testDataPath
+
+	v, err := New(opts)
+	require.NoError(t, err)
+
+	bloomOpts := DefaultBloomOptions()
+	bloomOpts.VerificationAttempts = 3
+	require.NoError(t, v.UseBloomFilter(testDataPath, bloomOpts))
+	require.Len(t, v.bloomSalted, 2)
+
+	assert.True(t, v.isDisposable("tempmail.com"))
+
+	// A domain that only collides in the primary filter is not a match
+	v.mu.Lock()
+	v.bloomFilter.Add([]byte("collision.com"))
+	v.mu.Unlock()
+	assert.False(t, v.isDisposable("collision.com"))
+
+	// Domains registered afterwards are added to every filter
+	v.RegisterDisposableDomains([]string{"new-disposable.com"})
+	assert.True(t, v.isDisposable("new-disposable.com"))
+
+	t.Run("save and load round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, v.SaveBloomFilter(&buf))
+
+		loaded, err := New(DefaultOptions())
+		require.NoError(t, err)
+		loaded.options.CheckDisposable = true
+		require.NoError(t, loaded.LoadBloomFilter(&buf))
+
+		assert.Equal(t, 3, loaded.bloomOptions.VerificationAttempts)
+		assert.Len(t, loaded.bloomSalted, 2)
+		assert.True(t, loaded.isDisposable("tempmail.com"))
+		assert.True(t, loaded.isDisposable("new-disposable.com"))
+		assert.False(t, loaded.isDisposable("collision.com"))
+	})
+}
+
+func TestBloomFilterTrustedDomainsURL(t *testing.T) {
+	testDataPath := "file://" + filepath.Join("testdata", "domains.json")
+	trustedPath := filepath.Join(t.TempDir(), "trusted.json")
+	require.NoError(t, os.WriteFile(trustedPath, []byte(`["collision.com"]`), 0644))
+
+	for _, cacheVerdicts := range []bool{false, true} {
+		opts := DefaultOptions()
+		opts.CheckDisposable = true
+		opts.RejectDisposable = true
+		opts.DisposableDomainsURL = testDataPath
+		opts.TrustedDomainsURL = "file://" + trustedPath
+		opts.CacheDomainVerdicts = cacheVerdicts
+
+		v, err := New(opts)
+		require.NoError(t, err)
+
+		bloomOpts := DefaultBloomOptions()
+		bloomOpts.VerificationAttempts = 3
+		require.NoError(t, v.UseBloomFilter(testDataPath, bloomOpts))
+
+		// Make the trusted domain collide in every filter, including the salted ones
+		v.mu.Lock()
+		v.addToBloomFilter("collision.com")
+		v.addToBloomFilter("untrusted-collision.com")
+		v.mu.Unlock()
+		require.True(t, v.testBloomFilter("collision.com"))
+
+		assert.False(t, v.IsDisposableDomain("collision.com"))
+		result := v.Validate("user@collision.com")
+		assert.True(t, result.IsValid)
+		assert.False(t, result.IsDisposable)
+
+		assert.True(t, v.IsDisposableDomain("untrusted-collision.com"))
+		assert.Equal(t, ReasonDisposable, v.Validate("user@untrusted-collision.com").Reason)
+	}
+}
+
+func TestUseBloomFilterFromSourcesSizing(t *testing.T) {
+	testDataPath := "file://" + filepath.Join("testdata", "domains.json")
+
+	v, err := New(DefaultOptions())
+	require.NoError(t, err)
+
+	bloomOpts := DefaultBloomOptions()
+	bloomOpts.VerificationAttempts = 2
+
+	// The filter is sized for the expected total, not the lists loaded so far
+	require.NoError(t, v.UseBloomFilterFromSources([]string{testDataPath}, 100000, bloomOpts))
+	m, _ := bloom.EstimateParameters(100000, bloomOpts.FalsePositiveRate)
+	assert.Equal(t, m, v.bloomFilter.Cap())
+	require.Len(t, v.bloomSalted, 1)
+	assert.Equal(t, m, v.bloomSalted[0].Cap())
+
+	// A low estimate doesn't undersize the filter: it's sized for the 2 x 240 domains found
+	require.NoError(t, v.UseBloomFilterFromSources([]string{testDataPath, testDataPath}, 1, bloomOpts))
+	m, _ = bloom.EstimateParameters(480, bloomOpts.FalsePositiveRate)
+	assert.Equal(t, m, v.bloomFilter.Cap())
+	assert.True(t, v.testBloomFilter("tempmail.com"))
+}
@@ -1,11 +1,17 @@
 package mailcop_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -122,6 +128,125 @@ func TestBloomFilter(t *testing.T) {
 	}
 }
 
+func TestBloomFilterTrustedDomains(t *testing.T) {
+	testDataPath := "file://" + filepath.Join("testdata", "domains.json")
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = testDataPath
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	err = v.UseBloomFilter(testDataPath, mailcop.DefaultBloomOptions())
+	require.NoError(t, err)
+
+	// Simulate a false positive by adding the domain to the filter directly
+	v.RegisterDisposableDomains([]string{"legit-company.com"})
+	require.True(t, v.Validate("user@legit-company.com").IsDisposable)
+
+	// Trusting the domain must override the bloom filter match
+	v.RegisterTrustedDomains([]string{"legit-company.com"})
+	result := v.Validate("user@legit-company.com")
+	assert.False(t, result.IsDisposable)
+	assert.True(t, result.IsValid)
+
+	// Untrusted domains in the filter are still flagged
+	assert.True(t, v.Validate("user@tempmail.com").IsDisposable)
+}
+
+func TestUseBloomFilterFromSources(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.json")
+	second := filepath.Join(dir, "second.json.gz")
+	require.NoError(t, os.WriteFile(first, []byte(`["mailinator.com", "yopmail.com"]`), 0644))
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, err := gz.Write([]byte(`["guerrillamail.com", "Sharklasers.com."]`))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, os.WriteFile(second, gzipped.Bytes(), 0644))
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.RejectDisposable = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	sources := []string{"file://" + first, "file://" + second}
+	require.NoError(t, v.UseBloomFilterFromSources(sources, 10000, mailcop.DefaultBloomOptions()))
+
+	// Domains from every source, and those already loaded, are in the filter
+	for _, domain := range []string{"mailinator.com", "yopmail.com", "guerrillamail.com", "sharklasers.com", "tempmail.com"} {
+		assert.True(t, v.IsDisposableDomain(domain), domain)
+	}
+	assert.False(t, v.IsDisposableDomain("company.org"))
+	assert.Equal(t, mailcop.ReasonDisposable, v.Validate("user@yopmail.com").Reason)
+
+	t.Run("errors", func(t *testing.T) {
+		assert.Error(t, v.UseBloomFilterFromSources(nil, 100, mailcop.DefaultBloomOptions()))
+
+		err := v.UseBloomFilterFromSources([]string{"file://" + first, "file://" + filepath.Join(dir, "missing.json")}, 100, mailcop.DefaultBloomOptions())
+		assert.ErrorContains(t, err, "missing.json")
+
+		// A failed source leaves the current filter in place
+		assert.True(t, v.IsDisposableDomain("guerrillamail.com"))
+	})
+}
+
+func TestUseBloomFilterAddsList(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	list := filepath.Join(t.TempDir(), "list.json")
+	require.NoError(t, os.WriteFile(list, []byte(`["mailinator.com", "yopmail.com"]`), 0644))
+	require.NoError(t, v.UseBloomFilter("file://"+list, mailcop.DefaultBloomOptions()))
+
+	// Domains from the list, and those already loaded, are in the filter
+	for _, domain := range []string{"mailinator.com", "yopmail.com", "tempmail.com"} {
+		assert.True(t, v.IsDisposableDomain(domain), domain)
+	}
+	assert.False(t, v.IsDisposableDomain("company.org"))
+
+	t.Run("validation isn't blocked while the list loads", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			_, _ = w.Write([]byte(`["sharklasers.com"]`))
+		}))
+		defer server.Close()
+
+		loaded := make(chan error, 1)
+		go func() {
+			loaded <- v.UseBloomFilter(server.URL, mailcop.DefaultBloomOptions())
+		}()
+		<-started
+
+		validated := make(chan mailcop.ValidationResult, 1)
+		go func() {
+			validated <- v.Validate("user@yopmail.com")
+		}()
+		select {
+		case result := <-validated:
+			assert.True(t, result.IsDisposable)
+		case <-time.After(5 * time.Second):
+			t.Error("Validate blocked while the list was loading")
+		}
+
+		close(release)
+		require.NoError(t, <-loaded)
+		assert.True(t, v.IsDisposableDomain("sharklasers.com"))
+	})
+}
+
 // formatBytes returns a human-readable string of bytes
 func formatBytes(b uint64) string {
 	const unit = 1024
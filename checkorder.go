@@ -0,0 +1,280 @@
+package mailcop
+
+import (
+	"context"
+	"fmt"
+)
+
+// CheckType identifies a domain check whose position in Options.CheckOrder can be
+// changed. Syntax, length, allowed pattern and TLD checks always run first, and
+// custom rules always run last.
+type CheckType int
+
+const (
+	CheckTypeIPDomain     CheckType = iota + 1 // IP address domains, including RequireIPReverseDNS
+	CheckTypeReserved                          // Reserved domains
+	CheckTypeDisposable                        // Disposable domains
+	CheckTypeFreeProvider                      // Free email providers
+	CheckTypeMX                                // MX lookup and the DNS checks that depend on it
+)
+
+// String returns the check as a short lowercase name like "mx"
+func (c CheckType) String() string {
+	switch c {
+	case CheckTypeIPDomain:
+		return "ip_domain"
+	case CheckTypeReserved:
+		return "reserved"
+	case CheckTypeDisposable:
+		return "disposable"
+	case CheckTypeFreeProvider:
+		return "free_provider"
+	case CheckTypeMX:
+		return "mx"
+	default:
+		return fmt.Sprintf("CheckType(%d)", int(c))
+	}
+}
+
+// MarshalText encodes the check as its String name, so CheckOrder reads naturally in JSON
+func (c CheckType) MarshalText() ([]byte, error) {
+	if c < CheckTypeIPDomain || c > CheckTypeMX {
+		return nil, fmt.Errorf("unknown check: %d", int(c))
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes a check name like "mx"
+func (c *CheckType) UnmarshalText(text []byte) error {
+	for check := CheckTypeIPDomain; check <= CheckTypeMX; check++ {
+		if check.String() == string(text) {
+			*c = check
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown check: %q", text)
+}
+
+// DefaultCheckOrder returns the order checks run in when Options.CheckOrder is empty
+func DefaultCheckOrder() []CheckType {
+	return []CheckType{
+		CheckTypeIPDomain,
+		CheckTypeReserved,
+		CheckTypeDisposable,
+		CheckTypeFreeProvider,
+		CheckTypeMX,
+	}
+}
+
+// validateCheckOrder reports unknown or repeated checks in a CheckOrder
+func validateCheckOrder(order []CheckType) error {
+	seen := make(map[CheckType]bool, len(order))
+	for _, check := range order {
+		if check < CheckTypeIPDomain || check > CheckTypeMX {
+			return fmt.Errorf("unknown check in CheckOrder: %v", check)
+		}
+		if seen[check] {
+			return fmt.Errorf("duplicate check in CheckOrder: %v", check)
+		}
+		seen[check] = true
+	}
+	return nil
+}
+
+// runCheck runs one check from the CheckOrder and reports whether validation should
+// stop with the result as it is
+func (v *Validator) runCheck(check CheckType, result *ValidationResult, domain string, verdict domainVerdict) bool {
+	switch check {
+	case CheckTypeIPDomain:
+		return v.checkIPDomain(result, domain, verdict)
+	case CheckTypeReserved:
+		return v.checkReserved(result, domain, verdict)
+	case CheckTypeDisposable:
+		return v.checkDisposable(result, domain, verdict)
+	case CheckTypeFreeProvider:
+		return v.checkFreeProvider(result, domain, verdict)
+	case CheckTypeMX:
+		return v.checkMX(result, domain)
+	}
+	return false
+}
+
+func (v *Validator) checkIPDomain(result *ValidationResult, domain string, verdict domainVerdict) bool {
+	if !verdict.ipDomain {
+		return false
+	}
+
+	result.IsIPDomain = true
+	if v.flag(result, CheckTypeIPDomain, v.options.RejectIPDomains, fmt.Errorf("IP address domains are not allowed"), ReasonIPDomain) {
+		return true
+	}
+
+	if v.options.RequireIPReverseDNS {
+		name, err := v.lookupReverseDNS(domain)
+		if err != nil && v.fail(result, fmt.Errorf("%w: %s: %v", ErrNoReverseDNS, domain, err), ReasonNoReverseDNS) {
+			return true
+		}
+		result.IPReverseDNS = name
+	}
+	return false
+}
+
+func (v *Validator) checkReserved(result *ValidationResult, domain string, verdict domainVerdict) bool {
+	if !verdict.reserved {
+		return false
+	}
+
+	result.IsReserved = true
+	if v.options.AllowLocalhost && isLocalhost(domain) {
+		return false
+	}
+	return v.flag(result, CheckTypeReserved, v.options.RejectReserved, fmt.Errorf("reserved domain: %s", domain), ReasonReserved)
+}
+
+func (v *Validator) checkDisposable(result *ValidationResult, domain string, verdict domainVerdict) bool {
+	if !verdict.disposable {
+		return false
+	}
+
+	result.IsDisposable = true
+	result.DisposableSource = verdict.disposableSource
+	result.DisposableConfidence = verdict.disposableCount
+	if verdict.disposableCount < v.options.DisposableMinSources {
+		return false
+	}
+	return v.flag(result, CheckTypeDisposable, v.options.RejectDisposable, fmt.Errorf("disposable domain: %s", domain), ReasonDisposable)
+}
+
+func (v *Validator) checkFreeProvider(result *ValidationResult, domain string, verdict domainVerdict) bool {
+	if !verdict.freeProvider {
+		return false
+	}
+
+	result.IsFreeProvider = true
+	return v.flag(result, CheckTypeFreeProvider, v.options.RejectFreeProvider, fmt.Errorf("free email provider: %s", domain), ReasonFreeProvider)
+}
+
+// checkMX looks up the domain's MX records and runs the checks that depend on them
+func (v *Validator) checkMX(result *ValidationResult, domain string) bool {
+	// With CollectAllErrors, skip the network round trips once a check has failed
+	if result.LastError != nil {
+		return false
+	}
+
+	// Read once so a concurrent SetCheckDNS can't change the setting mid-validation
+	checkDNS := v.checkDNS()
+
+	var mx DNSCacheEntry
+	var cacheHit bool
+	if checkDNS {
+		mx, cacheHit = v.lookupMX(context.Background(), domain)
+	}
+	result.DNSCacheHit = cacheHit
+	result.IsDNSSECValidated = mx.Authenticated
+	if checkDNS {
+		result.DNSStatus = classifyDNS(mx)
+	}
+	if mx.Err != nil && v.options.DNSFailOpen && result.DNSStatus.Transient() {
+		// A timeout or server failure says nothing about the domain, so carry on as if
+		// DNS checks were disabled
+		result.DNSInconclusive = true
+		checkDNS = false
+	} else if mx.Err != nil {
+		var addrs []string
+		if isNotFound(mx.Err) && (v.options.CheckReceivable || v.options.RejectPrivateIPResolution) {
+			addrs = v.lookupHost(domain)
+		}
+
+		// A domain without MX records that still resolves exists but can't receive mail
+		if err := v.checkPrivateIP(result, domain, addrs); err != nil {
+			v.fail(result, err, ReasonPrivateIP)
+		} else if v.options.CheckReceivable && len(addrs) > 0 {
+			v.fail(result, fmt.Errorf("%w: %s has no MX records", ErrNotReceivable, domain), ReasonNotReceivable)
+		} else {
+			v.fail(result, fmt.Errorf("invalid domain: %v", mx.Err), ReasonDNS)
+		}
+		return true
+	}
+
+	result.HasMX = checkDNS
+	result.CanReceiveMail = checkDNS && canReceiveMail(mx.MX)
+
+	// A null MX (RFC 7505) explicitly declares that the domain accepts no mail
+	if isNullMX(mx.MX) {
+		if v.fail(result, fmt.Errorf("%w: %s", ErrNullMX, domain), ReasonNullMX) {
+			return true
+		}
+	}
+
+	if v.options.RejectPrivateIPResolution && checkDNS && len(mx.MX) == 0 {
+		if err := v.checkPrivateIP(result, domain, v.lookupHost(domain)); err != nil && v.fail(result, err, ReasonPrivateIP) {
+			return true
+		}
+	}
+
+	if v.options.CheckReceivable && checkDNS && !result.CanReceiveMail && !isNullMX(mx.MX) {
+		if v.fail(result, fmt.Errorf("%w: %s has no MX records", ErrNotReceivable, domain), ReasonNotReceivable) {
+			return true
+		}
+	}
+
+	if checkDNS {
+		result.MXRecords = appendMXHosts(result.MXRecords, mx.MX)
+	}
+
+	// A single flaky MX is a common trait of throwaway fraud domains
+	if v.options.MinMXRecords > 0 && checkDNS {
+		if n := distinctHosts(result.MXRecords); n < v.options.MinMXRecords {
+			if v.fail(result, fmt.Errorf("%w: %s has %d, at least %d required", ErrTooFewMX, domain, n, v.options.MinMXRecords), ReasonTooFewMX) {
+				return true
+			}
+		}
+	}
+
+	if v.options.RequireDNSSEC && checkDNS && !mx.Authenticated {
+		if v.fail(result, fmt.Errorf("MX records for %s are not DNSSEC-validated", domain), ReasonDNSSEC) {
+			return true
+		}
+	}
+
+	if checkDNS {
+		result.MailProvider = v.detectMailProvider(mx.MX)
+	}
+
+	// Integrations gated on specific backends only accept domains hosted by them
+	if checkDNS && len(v.options.RequiredMXSuffixes) > 0 {
+		suffix, ok := matchMXSuffix(mx.MX, v.options.RequiredMXSuffixes)
+		if result.MailProvider == "" {
+			result.MailProvider = suffix
+		}
+		if !ok {
+			if v.fail(result, fmt.Errorf("%w: no MX host of %s matches RequiredMXSuffixes", ErrMailProviderNotAllowed, domain), ReasonMailProvider) {
+				return true
+			}
+		}
+	}
+
+	// Check if the domain's mail servers belong to a disposable service
+	if host, ok := v.isDisposableMX(domain, mx.MX); ok {
+		result.IsDisposable = true
+		result.DisposableSource = "mx:" + host
+		if v.flag(result, CheckTypeDisposable, v.options.RejectDisposable, fmt.Errorf("disposable mail server: %s", host), ReasonDisposable) {
+			return true
+		}
+	}
+
+	if checkDNS && v.options.CheckSPFExists {
+		result.HasSPF = v.hasSPF(domain)
+	}
+
+	// DMARC is informational unless RejectNoDMARC is set
+	if checkDNS && v.options.CheckDMARC {
+		result.HasDMARC = v.hasDMARC(domain)
+		if !result.HasDMARC && v.options.RejectNoDMARC {
+			if v.fail(result, fmt.Errorf("%w: %s", ErrNoDMARC, domain), ReasonNoDMARC) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package mailcop_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestCheckOrder(t *testing.T) {
+	newValidator := func(t *testing.T, order ...mailcop.CheckType) *mailcop.Validator {
+		opts := mailcop.DefaultOptions()
+		opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+		opts.CheckDisposable = true
+		opts.RejectDisposable = true
+		opts.RejectReserved = true
+		opts.CheckDNS = true
+		opts.Resolver = staticResolver{"company.org": {"mx.company.org"}}
+		opts.CheckOrder = order
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+		return v
+	}
+
+	t.Run("default order", func(t *testing.T) {
+		v := newValidator(t)
+
+		result := v.Validate("user@tempmail.com")
+		assert.False(t, result.IsValid)
+		assert.Equal(t, mailcop.ReasonDisposable, result.Reason)
+		assert.Equal(t, mailcop.DNSStatusNotChecked, result.DNSStatus)
+	})
+
+	t.Run("MX first", func(t *testing.T) {
+		v := newValidator(t, mailcop.CheckTypeMX, mailcop.CheckTypeDisposable)
+
+		result := v.Validate("user@tempmail.com")
+		assert.False(t, result.IsValid)
+		assert.Equal(t, mailcop.ReasonDNS, result.Reason)
+		assert.False(t, result.IsDisposable)
+
+		result = v.Validate("user@company.org")
+		assert.True(t, result.IsValid)
+		assert.True(t, result.HasMX)
+	})
+
+	t.Run("checks left out are skipped", func(t *testing.T) {
+		v := newValidator(t, mailcop.CheckTypeDisposable)
+
+		result := v.Validate("user@example.com")
+		assert.True(t, result.IsValid)
+		assert.False(t, result.IsReserved)
+		assert.False(t, result.HasMX)
+		assert.Equal(t, mailcop.DNSStatusNotChecked, result.DNSStatus)
+
+		result = v.Validate("user@tempmail.com")
+		assert.Equal(t, mailcop.ReasonDisposable, result.Reason)
+	})
+
+	t.Run("invalid orders", func(t *testing.T) {
+		for name, order := range map[string][]mailcop.CheckType{
+			"duplicate": {mailcop.CheckTypeMX, mailcop.CheckTypeMX},
+			"unknown":   {mailcop.CheckType(99)},
+		} {
+			opts := mailcop.DefaultOptions()
+			opts.CheckOrder = order
+
+			_, err := mailcop.New(opts)
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestCheckTypeString(t *testing.T) {
+	assert.Equal(t, "mx", mailcop.CheckTypeMX.String())
+	assert.Equal(t, "free_provider", mailcop.CheckTypeFreeProvider.String())
+	assert.Equal(t, "CheckType(99)", mailcop.CheckType(99).String())
+}
+
+func TestCheckOrderJSON(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckOrder = []mailcop.CheckType{mailcop.CheckTypeMX, mailcop.CheckTypeDisposable}
+
+	data, err := json.Marshal(opts)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"CheckOrder":["mx","disposable"]`)
+
+	var decoded mailcop.Options
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, opts.CheckOrder, decoded.CheckOrder)
+
+	err = json.Unmarshal([]byte(`{"CheckOrder":["spf"]}`), &decoded)
+	assert.Error(t, err)
+}
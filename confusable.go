@@ -0,0 +1,83 @@
+package mailcop
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps characters that are commonly used in homograph attacks to the
+// Latin character they resemble. It's a subset of the Unicode confusables table
+// (UTS #39) covering Cyrillic, Greek, Armenian and other lookalikes of a-z.
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'ԁ': 'd', 'е': 'e', 'ё': 'e', 'һ': 'h', 'і': 'i', 'ї': 'i', 'ј': 'j',
+	'к': 'k', 'ӏ': 'l', 'о': 'o', 'р': 'p', 'ԛ': 'q', 'ѕ': 's', 'ѵ': 'v', 'ԝ': 'w',
+	'х': 'x', 'у': 'y', 'ү': 'y', 'ԍ': 'g', 'ь': 'b', 'п': 'n',
+
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o',
+	'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x', 'γ': 'y', 'ω': 'w',
+
+	// Armenian
+	'օ': 'o', 'ս': 'u', 'ց': 'g', 'հ': 'h', 'ո': 'n', 'զ': 'q',
+
+	// Latin lookalikes and digits
+	'ı': 'i', 'ȷ': 'j', 'ł': 'l', 'ℓ': 'l', 'ƅ': 'b', 'ɑ': 'a', 'ɡ': 'g', 'ɩ': 'i', 'ʋ': 'u',
+	'0': 'o', '1': 'l',
+}
+
+// RegisterProtectedDomains adds domains, such as your own brands, that lookalike
+// domains are compared against when CheckConfusables is enabled.
+func (v *Validator) RegisterProtectedDomains(domains []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, domain := range domains {
+		domain = unicodeDomain(domain)
+		v.protectedDomains[skeleton(domain)] = domain
+	}
+}
+
+// isConfusable checks if a domain looks like a protected domain without being identical to it
+func (v *Validator) isConfusable(domain string) bool {
+	if !v.options.CheckConfusables {
+		return false
+	}
+
+	domain = unicodeDomain(domain)
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	protected, ok := v.protectedDomains[skeleton(domain)]
+	return ok && protected != domain
+}
+
+// unicodeDomain normalizes a domain and converts any punycode (xn--) labels to Unicode
+func unicodeDomain(domain string) string {
+	domain = normalizeDomain(domain)
+	if u, err := idna.ToUnicode(domain); err == nil {
+		return u
+	}
+	return domain
+}
+
+// skeleton reduces a domain to the Latin characters it visually resembles, so
+// lookalike domains share a skeleton. Diacritics are removed and confusable
+// characters are replaced using the confusables table.
+func skeleton(domain string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(domain) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if latin, ok := confusables[r]; ok {
+			r = latin
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,28 @@
+package mailcop
+
+import (
+	"fmt"
+	"sync"
+)
+
+// defaultValidator is the validator behind IsValid and ValidateDefault, created on first use
+var defaultValidator = sync.OnceValue(func() *Validator {
+	v, err := New(DefaultOptions())
+	if err != nil {
+		// DefaultOptions never loads anything, so this is a programming error
+		panic(fmt.Sprintf("mailcop: failed to create default validator: %v", err))
+	}
+	return v
+})
+
+// IsValid reports whether an email is valid using a validator with DefaultOptions, for
+// one-off checks where New isn't worth it. DNS, disposable and free provider checks
+// are off. The default validator is shared by all callers and safe for concurrent use.
+func IsValid(email string) bool {
+	return defaultValidator().Validate(email).IsValid
+}
+
+// ValidateDefault validates an email with the shared default validator used by IsValid
+func ValidateDefault(email string) ValidationResult {
+	return defaultValidator().Validate(email)
+}
@@ -0,0 +1,125 @@
+package mailcop
+
+import (
+	"container/list"
+	"net"
+	"sync"
+	"time"
+)
+
+// DNSCacheEntry is a cached MX lookup result.
+type DNSCacheEntry struct {
+	MX            []*net.MX // MX records returned by the lookup
+	Err           error     // Lookup error, if the lookup failed
+	Authenticated bool      // Whether the answer was DNSSEC-validated
+	CachedAt      time.Time // When the entry was cached; used for TTL expiry
+	Failures      int       // Consecutive failed lookups of the domain, including this one (0 when the lookup succeeded)
+}
+
+// DNSCache stores MX lookup results by domain. Implementations must be safe for
+// concurrent use and handle their own eviction. The method set matches
+// hashicorp/golang-lru/v2, so a *lru.Cache[string, DNSCacheEntry] can be used as is.
+// Expiry is handled by the Validator using DNSCacheTTL and DNSNegativeCacheTTL.
+type DNSCache interface {
+	Get(domain string) (DNSCacheEntry, bool)
+	Add(domain string, entry DNSCacheEntry) (evicted bool)
+	Remove(domain string) (present bool)
+	Len() int
+}
+
+// lruCache is a fixed-size map with least recently used eviction. It's the default
+// DNSCache and also backs the domain verdict cache.
+type lruCache[V any] struct {
+	mu    sync.Mutex
+	size  int
+	items map[string]*list.Element
+	order *list.List // Most recently used first; element values are *lruItem
+}
+
+// lruItem is an entry in the lruCache recency list
+type lruItem[V any] struct {
+	domain string
+	entry  V
+}
+
+// newLRUCache creates an LRU cache holding at most size entries
+func newLRUCache[V any](size int) *lruCache[V] {
+	return &lruCache[V]{
+		size:  size,
+		items: make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+// Get returns the entry for a domain and marks it as most recently used
+func (c *lruCache[V]) Get(domain string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[domain]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruItem[V]).entry, true
+}
+
+// Add stores an entry, replacing any existing entry for the domain and evicting the
+// least recently used entry in constant time when the cache is at capacity
+func (c *lruCache[V]) Add(domain string, entry V) (evicted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[domain]; ok {
+		elem.Value.(*lruItem[V]).entry = entry
+		c.order.MoveToFront(elem)
+		return false
+	}
+
+	for len(c.items) >= c.size && c.order.Len() > 0 {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruItem[V]).domain)
+		evicted = true
+	}
+
+	c.items[domain] = c.order.PushFront(&lruItem[V]{domain: domain, entry: entry})
+	return evicted
+}
+
+// Remove deletes the entry for a domain
+func (c *lruCache[V]) Remove(domain string) (present bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[domain]
+	if !ok {
+		return false
+	}
+
+	c.order.Remove(elem)
+	delete(c.items, domain)
+	return true
+}
+
+// Keys returns the cached domains from least to most recently used
+func (c *lruCache[V]) Keys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]string, 0, len(c.items))
+	for elem := c.order.Back(); elem != nil; elem = elem.Prev() {
+		keys = append(keys, elem.Value.(*lruItem[V]).domain)
+	}
+	return keys
+}
+
+// Len returns the number of cached entries
+func (c *lruCache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.items)
+}
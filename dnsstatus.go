@@ -0,0 +1,87 @@
+package mailcop
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// DNSStatus classifies the outcome of the MX lookup for a domain
+type DNSStatus int
+
+const (
+	DNSStatusNotChecked  DNSStatus = iota // DNS checks are disabled
+	DNSStatusOK                           // The domain has MX records
+	DNSStatusNXDomain                     // The domain doesn't exist
+	DNSStatusNoMX                         // The domain exists but has no MX records
+	DNSStatusNullMX                       // The domain publishes a null MX record (RFC 7505)
+	DNSStatusTimeout                      // The lookup timed out
+	DNSStatusServerError                  // The DNS server failed or returned an unexpected error
+)
+
+// String returns the status as a short lowercase name like "nxdomain"
+func (s DNSStatus) String() string {
+	switch s {
+	case DNSStatusNotChecked:
+		return "not_checked"
+	case DNSStatusOK:
+		return "ok"
+	case DNSStatusNXDomain:
+		return "nxdomain"
+	case DNSStatusNoMX:
+		return "no_mx"
+	case DNSStatusNullMX:
+		return "null_mx"
+	case DNSStatusTimeout:
+		return "timeout"
+	case DNSStatusServerError:
+		return "server_error"
+	default:
+		return "unknown"
+	}
+}
+
+// Transient reports whether the lookup failed for a reason that may go away on retry
+func (s DNSStatus) Transient() bool {
+	return s == DNSStatusTimeout || s == DNSStatusServerError
+}
+
+// errDNSTimeout is returned when a lookup exceeds DNSTimeout
+var errDNSTimeout = errors.New("DNS lookup timeout")
+
+// errNoMXRecords is wrapped by the not-found error returned when a domain exists but
+// has no MX records. Resolvers that can't tell this apart from NXDOMAIN, including
+// the standard library resolver, report both as NXDOMAIN.
+var errNoMXRecords = errors.New("no MX records")
+
+// classifyDNS returns the status of an MX lookup result
+func classifyDNS(entry DNSCacheEntry) DNSStatus {
+	if entry.Err == nil {
+		switch {
+		case len(entry.MX) == 0:
+			return DNSStatusNoMX
+		case isNullMX(entry.MX):
+			return DNSStatusNullMX
+		default:
+			return DNSStatusOK
+		}
+	}
+
+	if errors.Is(entry.Err, errNoMXRecords) {
+		return DNSStatusNoMX
+	}
+	if errors.Is(entry.Err, errDNSTimeout) || errors.Is(entry.Err, context.DeadlineExceeded) {
+		return DNSStatusTimeout
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(entry.Err, &dnsErr) {
+		switch {
+		case dnsErr.IsNotFound:
+			return DNSStatusNXDomain
+		case dnsErr.IsTimeout:
+			return DNSStatusTimeout
+		}
+	}
+	return DNSStatusServerError
+}
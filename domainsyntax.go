@@ -0,0 +1,78 @@
+package mailcop
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidDomainSyntax is returned in ValidationResult.LastError when a domain has an
+// empty label (as in "gmail..com" or ".example.com"), a label longer than 63 octets,
+// or a label with characters other than letters, digits and inner hyphens
+var ErrInvalidDomainSyntax = errors.New("invalid domain syntax")
+
+// ErrTooManyLabels is returned in ValidationResult.LastError when MaxDomainLabels is set
+// and a domain has more labels, as in "a.b.c.d.e.f.g.example.com"
+var ErrTooManyLabels = errors.New("domain has too many labels")
+
+// maxLabelLength is the maximum length of a domain label in octets (RFC 1035)
+const maxLabelLength = 63
+
+// checkDomainSyntax checks the syntax of a domain unless SkipDomainSyntaxCheck is set
+func (v *Validator) checkDomainSyntax(domain string) error {
+	if v.options.SkipDomainSyntaxCheck {
+		return nil
+	}
+	return checkDomainSyntax(domain)
+}
+
+// checkDomainSyntax checks each label of a domain, so malformed domains are rejected
+// whatever the parser let through. Letters and digits outside ASCII are allowed for
+// internationalized domains. Domain literals like "[192.0.2.1]" aren't checked.
+func checkDomainSyntax(domain string) error {
+	if strings.HasPrefix(domain, "[") {
+		return nil
+	}
+
+	for rest, more := domain, true; more; {
+		var label string
+		label, rest, more = strings.Cut(rest, ".")
+		if err := checkLabel(label); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidDomainSyntax, domain, err)
+		}
+	}
+	return nil
+}
+
+// checkLabel checks a single domain label. Errors don't repeat the label, since
+// checkDomainSyntax reports the whole domain and MaskDomainInResults masks it there.
+func checkLabel(label string) error {
+	switch {
+	case label == "":
+		return fmt.Errorf("empty label")
+	case len(label) > maxLabelLength:
+		return fmt.Errorf("label exceeds %d characters", maxLabelLength)
+	case label[0] == '-' || label[len(label)-1] == '-':
+		return fmt.Errorf("label starts or ends with a hyphen")
+	}
+
+	for _, r := range label {
+		if r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return fmt.Errorf("label contains %q", r)
+		}
+	}
+	return nil
+}
+
+// checkLabelCount checks that a domain has at most limit labels. Domain literals aren't
+// checked.
+func checkLabelCount(domain string, limit int) error {
+	if strings.HasPrefix(domain, "[") {
+		return nil
+	}
+	if n := strings.Count(domain, ".") + 1; n > limit {
+		return fmt.Errorf("%w: %s has %d, at most %d allowed", ErrTooManyLabels, domain, n, limit)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package mailcop
+
+import "strings"
+
+// RegisterExistingAddresses records addresses that are already registered, e.g. your
+// current users, so that Validate can flag new signups whose canonical form matches
+// one of them with IsDuplicate and DuplicateOf
+func (v *Validator) RegisterExistingAddresses(addresses []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.existingAddresses == nil {
+		v.existingAddresses = make(map[string]string, len(addresses))
+	}
+	for _, address := range addresses {
+		address = strings.TrimSpace(address)
+		if canonical := canonicalAddress(address); canonical != "" {
+			if _, ok := v.existingAddresses[canonical]; !ok {
+				v.existingAddresses[canonical] = address
+			}
+		}
+	}
+}
+
+// duplicateOf returns the registered address an address collides with, if any
+func (v *Validator) duplicateOf(address string) (string, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if len(v.existingAddresses) == 0 {
+		return "", false
+	}
+	existing, ok := v.existingAddresses[canonicalAddress(address)]
+	return existing, ok
+}
+
+// gmailDomains are the domains of Gmail, which ignores dots in the local part
+var gmailDomains = map[string]bool{"gmail.com": true, "googlemail.com": true}
+
+// canonicalAddress returns the form of an address used to detect duplicates: lowercased,
+// without a "+tag" subaddress, and for Gmail without dots in the local part and with
+// googlemail.com folded into gmail.com. It returns "" for strings without an "@".
+func canonicalAddress(address string) string {
+	at := strings.LastIndex(address, "@")
+	if at <= 0 {
+		return ""
+	}
+
+	local, domain := strings.ToLower(address[:at]), strings.ToLower(address[at+1:])
+	if base, _, ok := strings.Cut(local, "+"); ok && base != "" {
+		local = base
+	}
+	if gmailDomains[domain] {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+	return local + "@" + domain
+}
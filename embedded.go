@@ -0,0 +1,45 @@
+package mailcop
+
+import (
+	_ "embed"
+	"fmt"
+	"time"
+)
+
+// defaultDisposableDomainsURL is the disposable list fetched by default
+const defaultDisposableDomainsURL = "https://disposable.github.io/disposable-email-domains/domains.json"
+
+// embeddedSource is the source reported for domains from the bundled disposable list
+const embeddedSource = "embedded"
+
+// embeddedDisposableList is the bundled snapshot of disposable domains. "make
+// update-disposable" replaces it with the current upstream list and bumps
+// EmbeddedDisposableListDate to match.
+//
+//go:embed data/disposable_domains.json
+var embeddedDisposableList []byte
+
+// EmbeddedDisposableListDate is when the bundled disposable list was taken. Load a
+// fresher list with LoadDisposableDomains or a custom DisposableDomainsURL.
+var EmbeddedDisposableListDate = time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)
+
+// loadEmbeddedDisposableList loads the bundled disposable list. Its domains report
+// "embedded" as their source, and the list counts as updated on the snapshot date.
+func (v *Validator) loadEmbeddedDisposableList() error {
+	domains, err := parseProviderList(embeddedDisposableList)
+	if err != nil {
+		return fmt.Errorf("failed to parse embedded disposable list: %v", err)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	defer v.invalidateVerdicts()
+
+	v.disposableUpdated = EmbeddedDisposableListDate
+	for _, domain := range domains {
+		v.addDisposableDomain(domain, embeddedSource)
+	}
+
+	v.logger.Debug("list refreshed", "list", "disposable", "url", embeddedSource, "count", len(domains))
+	return nil
+}
@@ -0,0 +1,206 @@
+package mailcop
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// explainStep is a group of checks reported on one line by Explain, in the order
+// validation runs them
+type explainStep struct {
+	name    string
+	failed  func(result ValidationResult) bool // Whether the result was rejected at this step
+	outcome func(v *Validator, result ValidationResult) string
+}
+
+// explainSteps lists the checks described by Explain
+var explainSteps = []explainStep{
+	{
+		name:   "Syntax",
+		failed: failedWith(ReasonSyntax, ReasonTooLong, ReasonNamed, ReasonNonASCII, ReasonDisplayNameSpoof, ReasonUTF8LocalPart),
+		outcome: func(_ *Validator, result ValidationResult) string {
+			if result.Name != "" {
+				return fmt.Sprintf("ok (address %s, name %q)", result.Address, result.Name)
+			}
+			return fmt.Sprintf("ok (address %s)", result.Address)
+		},
+	},
+	{
+		name:   "Local part",
+		failed: failedWith(ReasonBannedLocalPart),
+		outcome: func(_ *Validator, _ ValidationResult) string {
+			return "ok"
+		},
+	},
+	{
+		name:   "Domain",
+		failed: failedWith(ReasonDomainTooShort, ReasonTooManyLabels, ReasonDomainNotAllowed, ReasonInvalidIDN, ReasonPublicSuffix),
+		outcome: func(_ *Validator, result ValidationResult) string {
+			return "ok (" + result.Domain + ")"
+		},
+	},
+	{
+		name:   "IP domain",
+		failed: failedWith(ReasonIPDomain, ReasonNoReverseDNS),
+		outcome: func(_ *Validator, result ValidationResult) string {
+			if result.IPReverseDNS != "" {
+				return "yes (reverse DNS " + result.IPReverseDNS + ")"
+			}
+			return yesNo(result.IsIPDomain)
+		},
+	},
+	{
+		name:   "TLD",
+		failed: failedWith(ReasonInvalidTLD, ReasonUnknownTLD),
+		outcome: func(v *Validator, result ValidationResult) string {
+			switch {
+			case result.IsIPDomain:
+				return "not checked (IP domain)"
+			case !v.options.CheckTLD:
+				return "ok (list not checked)"
+			case result.IsValidTLD:
+				return "ok (known)"
+			default:
+				return "unknown"
+			}
+		},
+	},
+	{
+		name:   "Reserved",
+		failed: failedWith(ReasonReserved),
+		outcome: func(_ *Validator, result ValidationResult) string {
+			return yesNo(result.IsReserved)
+		},
+	},
+	{
+		name: "Disposable",
+		failed: func(result ValidationResult) bool {
+			return result.Reason == ReasonDisposable && !strings.HasPrefix(result.DisposableSource, "mx:")
+		},
+		outcome: func(v *Validator, result ValidationResult) string {
+			if !v.options.CheckDisposable {
+				return "not checked"
+			}
+			if result.IsDisposable && result.DisposableSource != "" {
+				return "yes (source: " + result.DisposableSource + ")"
+			}
+			return yesNo(result.IsDisposable)
+		},
+	},
+	{
+		name:   "Free provider",
+		failed: failedWith(ReasonFreeProvider),
+		outcome: func(v *Validator, result ValidationResult) string {
+			if !v.options.CheckFreeProvider {
+				return "not checked"
+			}
+			return yesNo(result.IsFreeProvider)
+		},
+	},
+	{
+		name: "MX",
+		failed: func(result ValidationResult) bool {
+			// Disposable mail servers are only known once the MX records are in
+			return failedWith(ReasonDNS, ReasonNullMX, ReasonNotReceivable, ReasonDNSSEC, ReasonMailProvider, ReasonTooFewMX, ReasonPrivateIP)(result) ||
+				result.Reason == ReasonDisposable && strings.HasPrefix(result.DisposableSource, "mx:")
+		},
+		outcome: func(v *Validator, result ValidationResult) string {
+			if result.DNSInconclusive {
+				return "inconclusive (lookup failed, accepted by DNSFailOpen)"
+			}
+			if !result.HasMX {
+				return "not checked"
+			}
+
+			var notes []string
+			if result.DNSCacheHit {
+				notes = append(notes, "cached")
+			}
+			if result.IsDNSSECValidated {
+				notes = append(notes, "DNSSEC-validated")
+			}
+			if result.MailProvider != "" {
+				notes = append(notes, "provider "+result.MailProvider)
+			}
+			if len(notes) == 0 {
+				return "found"
+			}
+			return "found (" + strings.Join(notes, ", ") + ")"
+		},
+	},
+	{
+		name:   "DMARC",
+		failed: failedWith(ReasonNoDMARC),
+		outcome: func(v *Validator, result ValidationResult) string {
+			if !v.options.CheckDMARC || !result.HasMX {
+				return "not checked"
+			}
+			return yesNo(result.HasDMARC)
+		},
+	},
+	{
+		name:   "Custom rules",
+		failed: failedWith(ReasonCustomRule),
+		outcome: func(v *Validator, _ ValidationResult) string {
+			if len(v.options.CustomRules) == 0 {
+				return "none"
+			}
+			return "passed"
+		},
+	},
+}
+
+// Explain validates an email and describes the outcome of each check, one per line,
+// for support tooling and other non-engineering audiences. Checks after the one that
+// rejected the email are reported as not reached, and warnings follow the outcome.
+// Explain doesn't count towards Stats.
+func (v *Validator) Explain(email string) string {
+	result := v.validate(email)
+	result.Score = v.score(result)
+
+	var b strings.Builder
+	if result.IsValid {
+		fmt.Fprintf(&b, "Accepted: %s (score %.2f)\n", result.Address, result.Score)
+	} else {
+		fmt.Fprintf(&b, "Rejected: %s\n", result.ErrorMessage())
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(&b, "Warning: %s\n", warning)
+	}
+
+	reached := true
+	for _, step := range explainSteps {
+		var outcome string
+		switch {
+		case !reached:
+			outcome = "not reached"
+		case step.failed(result):
+			outcome = "failed: " + result.ErrorMessage()
+			if result.Reason == ReasonDisposable && result.DisposableSource != "" {
+				outcome += " (source: " + result.DisposableSource + ")"
+			}
+			reached = false
+		default:
+			outcome = step.outcome(v, result)
+		}
+		fmt.Fprintf(&b, "%s: %s\n", step.name, outcome)
+	}
+
+	return b.String()
+}
+
+// failedWith returns a check that a result was rejected with one of reasons
+func failedWith(reasons ...Reason) func(ValidationResult) bool {
+	return func(result ValidationResult) bool {
+		return slices.Contains(reasons, result.Reason)
+	}
+}
+
+// yesNo formats a check's flag for Explain
+func yesNo(flag bool) string {
+	if flag {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,67 @@
+package mailcop_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestExplain(t *testing.T) {
+	disposableURL := "file://" + filepath.Join("testdata", "domains.json")
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.CheckFreeProvider = true
+	opts.DisposableDomainsURL = disposableURL
+	opts.RejectDisposable = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	t.Run("rejected", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(v.Explain("user@tempmail.com")), "\n")
+		assert.Equal(t, []string{
+			"Rejected: disposable domain: tempmail.com",
+			"Syntax: ok (address user@tempmail.com)",
+			"Local part: ok",
+			"Domain: ok (tempmail.com)",
+			"IP domain: no",
+			"TLD: ok (list not checked)",
+			"Reserved: no",
+			"Disposable: failed: disposable domain: tempmail.com (source: " + disposableURL + ")",
+			"Free provider: not reached",
+			"MX: not reached",
+			"DMARC: not reached",
+			"Custom rules: not reached",
+		}, lines)
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		explanation := v.Explain("not an email")
+		assert.True(t, strings.HasPrefix(explanation, "Rejected: invalid email format"))
+		assert.Contains(t, explanation, "Syntax: failed: invalid email format")
+		assert.Contains(t, explanation, "Domain: not reached")
+	})
+
+	t.Run("accepted with DNS", func(t *testing.T) {
+		opts := opts
+		opts.CheckDNS = true
+		opts.Resolver = staticResolver{"gmail.com": {"gmail-smtp-in.l.google.com"}}
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		explanation := v.Explain(`"Jane" <jane@gmail.com>`)
+		assert.True(t, strings.HasPrefix(explanation, "Accepted: jane@gmail.com (score "))
+		assert.Contains(t, explanation, `Syntax: ok (address jane@gmail.com, name "Jane")`)
+		assert.Contains(t, explanation, "Free provider: yes\n")
+		assert.Contains(t, explanation, "MX: found (provider google)\n")
+		assert.Contains(t, explanation, "Custom rules: none\n")
+		assert.Zero(t, v.Stats().Validated)
+	})
+}
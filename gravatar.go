@@ -0,0 +1,14 @@
+package mailcop
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strings"
+)
+
+// GravatarHash returns the Gravatar hash for an email address. Per the Gravatar
+// spec, the address is trimmed and lowercased before being MD5-hashed.
+func GravatarHash(email string) string {
+	sum := md5.Sum([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
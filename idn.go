@@ -0,0 +1,51 @@
+package mailcop
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrInvalidIDN is returned in ValidationResult.LastError when RejectInvalidIDN is set
+// and an internationalized domain's Unicode and punycode forms don't round-trip, a sign
+// of malformed or deceptive encoding
+var ErrInvalidIDN = errors.New("invalid internationalized domain")
+
+// checkIDN checks that each internationalized label of a domain survives the round trip
+// between its Unicode and punycode (xn--) forms. A punycode label must decode to Unicode
+// that encodes back to the same label, so "xn--example-", which decodes to plain
+// "example", is rejected. A Unicode label must already be in the form IDNA maps it to,
+// apart from NFC normalization, so fullwidth letters that map to ASCII are rejected.
+// ASCII labels and domain literals aren't checked.
+func checkIDN(domain string) error {
+	if strings.HasPrefix(domain, "[") {
+		return nil
+	}
+
+	for _, label := range strings.Split(domain, ".") {
+		punycode := strings.HasPrefix(label, "xn--")
+		if !punycode && isASCII(label) {
+			continue
+		}
+
+		decoded, err := idna.Lookup.ToUnicode(label)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidIDN, domain, err)
+		}
+		encoded, err := idna.Lookup.ToASCII(decoded)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidIDN, domain, err)
+		}
+
+		switch {
+		case punycode && (encoded != label || isASCII(decoded)):
+			return fmt.Errorf("%w: %s: %s doesn't round-trip through %q", ErrInvalidIDN, domain, label, decoded)
+		case !punycode && decoded != norm.NFC.String(label):
+			return fmt.Errorf("%w: %s: %s doesn't round-trip through %q", ErrInvalidIDN, domain, label, encoded)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package mailcop_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestRejectInvalidIDN(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.RejectInvalidIDN = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		domain string
+		valid  bool
+	}{
+		{name: "ascii", domain: "example.com", valid: true},
+		{name: "unicode", domain: "bücher.de", valid: true},
+		{name: "punycode", domain: "xn--bcher-kva.de", valid: true},
+		{name: "uppercase punycode", domain: "XN--BCHER-KVA.de", valid: true},
+		{name: "japanese", domain: "例え.jp", valid: true},
+		{name: "sharp s kept by nontransitional processing", domain: "straße.de", valid: true},
+		{name: "fullwidth letters mapping to ascii", domain: "ｇｏｏｇｌｅ.com"},
+		{name: "fullwidth letter among ascii", domain: "ｅxample.com"},
+		{name: "undecodable punycode", domain: "xn--abc.com"},
+		{name: "punycode of a disallowed character", domain: "xn--a-ecp.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateDomain(tt.domain)
+			assert.Equal(t, tt.valid, result.IsValid, result.LastError)
+			if !tt.valid {
+				assert.ErrorIs(t, result.LastError, mailcop.ErrInvalidIDN)
+				assert.Equal(t, mailcop.ReasonInvalidIDN, result.Reason)
+			}
+		})
+	}
+
+	t.Run("addresses", func(t *testing.T) {
+		assert.True(t, v.Validate("user@bücher.de").IsValid)
+		assert.Equal(t, mailcop.ReasonInvalidIDN, v.Validate("user@ｇｏｏｇｌｅ.com").Reason)
+		assert.Contains(t, v.Explain("user@ｇｏｏｇｌｅ.com"), "Domain: failed: ")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.DefaultOptions())
+		require.NoError(t, err)
+		assert.True(t, v.ValidateDomain("ｇｏｏｇｌｅ.com").IsValid)
+	})
+}
@@ -0,0 +1,67 @@
+package mailcop
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// DetailedResult is a ValidationResult along with the values derived from the address
+// along the way, for account management UIs that need every piece of it
+type DetailedResult struct {
+	ValidationResult
+
+	ASCIIDomain      string // Domain with Unicode labels converted to punycode, e.g. "xn--bcher-kva.de" for "bücher.de"
+	CanonicalAddress string // Form used to detect duplicates, e.g. "jane@gmail.com" for "J.ane+news@googlemail.com"
+	LocalPart        string // Local part of Address, e.g. "jane+news"
+	UnicodeDomain    string // Domain with punycode labels converted to Unicode, e.g. "bücher.de" for "xn--bcher-kva.de"
+}
+
+// Inspect validates an email like Validate and also returns the values derived from
+// it, so callers don't have to parse and normalize the address again. The derived
+// values are empty when the email can't be parsed, and are masked like the result
+// with MaskInResults.
+func (v *Validator) Inspect(email string) DetailedResult {
+	result := v.validate(email)
+
+	var detailed DetailedResult
+	if at := strings.LastIndexByte(result.Address, '@'); at > 0 {
+		detailed.LocalPart = result.Address[:at]
+		detailed.CanonicalAddress = canonicalAddress(result.Address)
+	}
+	if result.Domain != "" {
+		detailed.ASCIIDomain = asciiDomain(result.Domain)
+		detailed.UnicodeDomain = unicodeDomain(result.Domain)
+	}
+
+	detailed.ValidationResult = v.finalize(result)
+	v.maskDetails(&detailed)
+	return detailed
+}
+
+// asciiDomain converts any Unicode labels of a domain to punycode, returning the
+// domain as it is when it can't be converted, e.g. for domain literals
+func asciiDomain(domain string) string {
+	if a, err := idna.ToASCII(domain); err == nil {
+		return a
+	}
+	return domain
+}
+
+// maskDetails masks the derived values of a detailed result like maskResult masks
+// the result itself
+func (v *Validator) maskDetails(detailed *DetailedResult) {
+	if !v.options.MaskInResults {
+		return
+	}
+	maskDomain := v.options.MaskDomainInResults
+
+	detailed.LocalPart = maskLocalPart(detailed.LocalPart)
+	if detailed.CanonicalAddress != "" {
+		detailed.CanonicalAddress = maskAddress(detailed.CanonicalAddress, maskDomain)
+	}
+	if maskDomain {
+		detailed.ASCIIDomain = maskDomainName(detailed.ASCIIDomain)
+		detailed.UnicodeDomain = maskDomainName(detailed.UnicodeDomain)
+	}
+}
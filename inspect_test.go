@@ -0,0 +1,95 @@
+package mailcop_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestInspect(t *testing.T) {
+	v, err := mailcop.New(mailcop.DefaultOptions())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		email         string
+		valid         bool
+		wantName      string
+		wantLocalPart string
+		wantDomain    string
+		wantCanonical string
+		wantASCII     string
+		wantUnicode   string
+	}{
+		{
+			name:          "named gmail address",
+			email:         "Jane Doe <J.ane+news@GoogleMail.com>",
+			valid:         true,
+			wantName:      "Jane Doe",
+			wantLocalPart: "J.ane+news",
+			wantDomain:    "googlemail.com",
+			wantCanonical: "jane@gmail.com",
+			wantASCII:     "googlemail.com",
+			wantUnicode:   "googlemail.com",
+		},
+		{
+			name:          "unicode domain",
+			email:         "user@bücher.de",
+			valid:         true,
+			wantLocalPart: "user",
+			wantDomain:    "bücher.de",
+			wantCanonical: "user@bücher.de",
+			wantASCII:     "xn--bcher-kva.de",
+			wantUnicode:   "bücher.de",
+		},
+		{
+			name:          "punycode domain",
+			email:         "user@xn--bcher-kva.de",
+			valid:         true,
+			wantLocalPart: "user",
+			wantDomain:    "xn--bcher-kva.de",
+			wantCanonical: "user@xn--bcher-kva.de",
+			wantASCII:     "xn--bcher-kva.de",
+			wantUnicode:   "bücher.de",
+		},
+		{
+			name:  "unparseable",
+			email: "not an email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detailed := v.Inspect(tt.email)
+			assert.Equal(t, tt.valid, detailed.IsValid, detailed.LastError)
+			assert.Equal(t, tt.wantName, detailed.Name)
+			assert.Equal(t, tt.wantLocalPart, detailed.LocalPart)
+			assert.Equal(t, tt.wantDomain, detailed.Domain)
+			assert.Equal(t, tt.wantCanonical, detailed.CanonicalAddress)
+			assert.Equal(t, tt.wantASCII, detailed.ASCIIDomain)
+			assert.Equal(t, tt.wantUnicode, detailed.UnicodeDomain)
+		})
+	}
+
+	t.Run("matches Validate", func(t *testing.T) {
+		detailed := v.Inspect("user@sub.example.co.uk")
+		assert.Equal(t, "example.co.uk", detailed.RegistrableDomain)
+		assert.Equal(t, v.Validate("user@sub.example.co.uk").Address, detailed.Address)
+	})
+
+	t.Run("masked", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.MaskInResults = true
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		detailed := v.Inspect("jane+news@example.com")
+		assert.Equal(t, "j*******s@example.com", detailed.Address)
+		assert.Equal(t, "j*******s", detailed.LocalPart)
+		assert.Equal(t, "j**e@example.com", detailed.CanonicalAddress)
+		assert.Equal(t, "example.com", detailed.ASCIIDomain)
+	})
+}
@@ -1,24 +1,57 @@
 package mailcop
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net"
 	"strings"
 )
 
 // isIPDomain checks if a domain is an IP address
 func (v *Validator) isIPDomain(domain string) bool {
+	return parseIPDomain(domain) != nil
+}
+
+// parseIPDomain returns the IP address of an IP domain, or nil if it isn't one
+func parseIPDomain(domain string) net.IP {
 	// Only handle bracketed IP addresses
 	if strings.HasPrefix(domain, "[") && strings.HasSuffix(domain, "]") {
 		// Remove brackets
 		ipStr := domain[1 : len(domain)-1]
-		// Handle IPv6 format with prefix
-		ipStr = strings.TrimPrefix(ipStr, "IPv6:")
-
-		if ip := net.ParseIP(ipStr); ip != nil {
-			return true // Valid IPv4 or IPv6
+		// Handle IPv6 format with prefix (case-insensitive, as domains may be lowercased)
+		if len(ipStr) > 5 && strings.EqualFold(ipStr[:5], "IPv6:") {
+			ipStr = ipStr[5:]
 		}
-	} else if ip := net.ParseIP(domain); ip != nil {
-		return true // Valid IPv4 or IPv6
+
+		return net.ParseIP(ipStr)
+	}
+
+	// Skip parsing ordinary domains: IPv4 addresses end in a digit and IPv6 ones have colons
+	if domain == "" || !strings.Contains(domain, ":") && (domain[len(domain)-1] < '0' || domain[len(domain)-1] > '9') {
+		return nil
+	}
+	return net.ParseIP(domain)
+}
+
+// ErrNoReverseDNS is returned in ValidationResult.LastError when RequireIPReverseDNS is
+// set and an IP domain has no PTR record
+var ErrNoReverseDNS = errors.New("IP address has no reverse DNS")
+
+// lookupReverseDNS returns the first PTR name of an IP domain, without its trailing
+// dot. The validator's resolver must be a ReverseResolver, which New enforces.
+func (v *Validator) lookupReverseDNS(domain string) (string, error) {
+	resolver := v.resolver.(ReverseResolver)
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.options.DNSTimeout)
+	defer cancel()
+
+	names, err := resolver.LookupAddr(ctx, parseIPDomain(domain).String())
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("no PTR records")
 	}
-	return false
+	return strings.TrimSuffix(names[0], "."), nil
 }
@@ -0,0 +1,31 @@
+package mailcop
+
+import "sort"
+
+// ListUpdate describes what changed when a disposable list was loaded again. It's
+// passed to Options.OnListUpdate, e.g. to alert when a list suddenly shrinks because
+// of a problem upstream. Domains dropped from a list stay loaded.
+type ListUpdate struct {
+	URL      string   // List that was loaded
+	Added    []string // Domains that weren't in the list at its last load, sorted (nil with a bloom filter)
+	Removed  []string // Domains in the list at its last load that aren't anymore, sorted (nil with a bloom filter)
+	Count    int      // Number of domains in the list now
+	Previous int      // Number of domains in the list at its last load
+}
+
+// diffDomains returns the domains only in current and the domains only in previous
+func diffDomains(previous, current map[string]struct{}) (added, removed []string) {
+	for domain := range current {
+		if _, ok := previous[domain]; !ok {
+			added = append(added, domain)
+		}
+	}
+	for domain := range previous {
+		if _, ok := current[domain]; !ok {
+			removed = append(removed, domain)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
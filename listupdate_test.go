@@ -0,0 +1,65 @@
+package mailcop_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestOnListUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disposable.json")
+	writeList := func(list string) {
+		require.NoError(t, os.WriteFile(path, []byte(list), 0644))
+	}
+	writeList(`["a.com", "b.com", "c.com"]`)
+
+	var mu sync.Mutex
+	var updates []mailcop.ListUpdate
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = "file://" + path
+	opts.OnListUpdate = func(update mailcop.ListUpdate) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, update)
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	assert.Empty(t, updates, "not called for the first load")
+
+	writeList(`["B.com", "c.com", "d.com", "e.com"]`)
+	require.NoError(t, v.LoadDisposableDomains(opts.DisposableDomainsURL))
+	require.Len(t, updates, 1)
+	assert.Equal(t, mailcop.ListUpdate{
+		URL:      opts.DisposableDomainsURL,
+		Added:    []string{"d.com", "e.com"},
+		Removed:  []string{"a.com"},
+		Count:    4,
+		Previous: 3,
+	}, updates[0])
+
+	// Dropped domains stay loaded, and aren't reported again
+	assert.True(t, v.IsDisposableDomain("a.com"))
+	require.NoError(t, v.LoadDisposableDomains(opts.DisposableDomainsURL))
+	require.Len(t, updates, 2)
+	assert.Empty(t, updates[1].Added)
+	assert.Empty(t, updates[1].Removed)
+	assert.Equal(t, 4, updates[1].Count)
+
+	t.Run("bloom filter", func(t *testing.T) {
+		require.NoError(t, v.UseBloomFilter(opts.DisposableDomainsURL, mailcop.DefaultBloomOptions()))
+
+		writeList(`["c.com"]`)
+		require.NoError(t, v.LoadDisposableDomains(opts.DisposableDomainsURL))
+		require.Len(t, updates, 3)
+		assert.Equal(t, mailcop.ListUpdate{URL: opts.DisposableDomainsURL, Count: 1, Previous: 4}, updates[2])
+	})
+}
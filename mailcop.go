@@ -1,33 +1,99 @@
 package mailcop
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/mail"
+	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/bits-and-blooms/bloom/v3"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/text/unicode/norm"
 )
 
 // Options contains configuration options for email validation
 type Options struct {
-	CheckDNS             bool          // Whether to perform DNS MX lookup
-	CheckDisposable      bool          // Whether to check for disposable domains
-	CheckFreeProvider    bool          // Whether to check for free email providers
-	DNSCacheTTL          time.Duration // TTL for DNS cache
-	DNSCacheSize         int           // Maximum number of DNS cache entries
-	DNSTimeout           time.Duration // Timeout for DNS lookups
-	DisposableDomainsURL string        // URL for disposable domains list
-	FreeProvidersURL     string        // URL for free email providers list
-	MaxEmailLength       int           // Maximum email length
-	MinDomainLength      int           // Minimum domain length
-	RejectDisposable     bool          // Whether to invalidate disposable domains
-	RejectFreeProvider   bool          // Whether to invalidate free email providers
-	RejectIPDomains      bool          // Whether to reject IP address domains
-	RejectNamedEmails    bool          // Whether to reject named email addresses (e.g. "First Last <first.last@example.com>")
-	RejectReserved       bool          // Whether to invalidate reserved example domains
-	TrustedDomainsURL    string        // URL for trusted domains list
+	ASCIIOnly                 bool                           // Whether to reject addresses with non-ASCII characters in the local part or domain
+	AllowLocalhost            bool                           // Whether to accept localhost and *.localhost even with RejectReserved (IsReserved is still set)
+	AllowedDomainPatterns     []string                       // Globs (e.g. "*.acme.com") or /regex/ patterns; other domains are rejected
+	BannedLocalParts          []string                       // Local parts to reject outright (e.g. "test"), matched case-insensitively
+	BannedLocalPartsURL       string                         // URL for a JSON list of banned local parts
+	CacheDomainVerdicts       bool                           // Whether to memoize the IP, reserved, disposable and free provider verdicts per domain
+	CheckDMARC                bool                           // Whether to look up whether the domain publishes a DMARC record (requires CheckDNS and a TXTResolver)
+	CheckDNS                  bool                           // Whether to perform DNS MX lookup
+	CheckDisposable           bool                           // Whether to check for disposable domains
+	CheckFreeProvider         bool                           // Whether to check for free email providers
+	CheckConfusables          bool                           // Whether to flag lookalike domains of protected domains
+	CheckOrder                []CheckType                    // Order of the domain checks; checks left out are skipped (defaults to DefaultCheckOrder)
+	CheckReceivable           bool                           // Whether to reject domains without a real MX record (requires CheckDNS)
+	CheckSPFExists            bool                           // Whether to look up whether the domain publishes an SPF record (requires CheckDNS and a TXTResolver)
+	CheckTLD                  bool                           // Whether to check the TLD against the IANA list
+	CollectAllErrors          bool                           // Whether to run every check and collect each failure in ValidationResult.Errors instead of stopping at the first
+	CustomRules               []func(ValidationResult) error // Extra rules run after the built-in checks pass; an error invalidates the result
+	DNSCache                  DNSCache                       // DNS cache implementation (defaults to an LRU cache of DNSCacheSize entries)
+	DNSCacheTTL               time.Duration                  // TTL for DNS cache
+	DNSNegativeCacheTTL       time.Duration                  // TTL for cached failed DNS lookups
+	DNSCacheSize              int                            // Maximum number of DNS cache entries
+	DNSFailOpen               bool                           // Whether to accept domains whose MX lookup fails transiently (timeout or server failure)
+	DNSTimeout                time.Duration                  // Timeout for DNS lookups
+	DedupInput                bool                           // Whether ValidateMany validates duplicate addresses once and copies the result
+	DetectDisplayNameSpoof    bool                           // Whether to flag display names containing an address at a different domain
+	DisposableDomainsURL      string                         // URL for disposable domains list
+	DisposableMinSources      int                            // Minimum number of lists that must include a domain for RejectDisposable to reject it (0 or 1 means any)
+	DisposableMXHosts         []string                       // MX hosts of disposable services (e.g. "mx.mailinator.com" or "*.mailinator.com")
+	FreeProviderMatchVariants bool                           // Match regional variants of free providers (e.g. yahoo.fr for yahoo.com) and "yahoo.*" patterns
+	FreeProvidersURL          string                         // URL for free email providers list
+	GravatarHash              bool                           // Whether to populate ValidationResult.GravatarHash
+	ListFetchRetries          int                            // Times to retry a remote list fetch that fails with a network error or 5xx status
+	ListFetchRetryDelay       time.Duration                  // Delay before the first retry, doubled after each one
+	Logger                    *slog.Logger                   // Receives debug logs for list loads and DNS lookups (nil disables logging)
+	MaskDomainInResults       bool                           // Whether MaskInResults also masks the domain, including Domain and RegistrableDomain
+	MaskInResults             bool                           // Whether to mask addresses in results, as MaskEmail does, so they can be logged (the domain is kept unless MaskDomainInResults is set)
+	MatchSubdomains           bool                           // Whether subdomains of disposable domains are disposable too (map-based validation only)
+	MaxConcurrency            int                            // Maximum concurrent validations in ValidateMany (0 means unlimited)
+	MaxDomainLabels           int                            // Maximum number of labels in the domain, e.g. 3 for "mail.example.com" (0 disables)
+	MaxEmailLength            int                            // Maximum email length
+	MinDomainLength           int                            // Minimum domain length
+	MinMXRecords              int                            // Minimum number of distinct MX hosts a domain must have (0 disables; requires CheckDNS)
+	MinTLDLength              int                            // Minimum length of the top-level domain label (0 disables)
+	NormalizeUnicode          bool                           // Whether to apply Unicode NFC normalization to the address before the checks, storing the normalized form in Address
+	OnListUpdate              func(ListUpdate)               // Called when a disposable list is loaded again, with what changed since its last load
+	PreserveDomainCase        bool                           // Whether to keep the domain's case in the stored Address instead of lowercasing it (the local part is never changed)
+	RejectDisplayNameSpoof    bool                           // Whether to reject display name spoofs (only with DetectDisplayNameSpoof)
+	RejectDisposable          bool                           // Whether to invalidate disposable domains
+	RejectFreeProvider        bool                           // Whether to invalidate free email providers
+	RejectIPDomains           bool                           // Whether to reject IP address domains
+	RejectInvalidIDN          bool                           // Whether to reject internationalized domains whose Unicode and punycode forms don't round-trip (ErrInvalidIDN)
+	RejectNamedEmails         bool                           // Whether to reject named email addresses (e.g. "First Last <first.last@example.com>")
+	RejectNoDMARC             bool                           // Whether to reject domains without a DMARC record (only with CheckDMARC)
+	RejectPrivateIPResolution bool                           // Whether to reject domains without MX records that resolve to a private, loopback or link-local address (requires CheckDNS and a HostResolver)
+	RejectPublicSuffixDomains bool                           // Whether to reject domains that are exactly a public suffix (e.g. "co.uk" or "github.io")
+	RejectReserved            bool                           // Whether to invalidate reserved example domains
+	RejectUTF8LocalPart       bool                           // Whether to reject non-ASCII local parts, which require SMTPUTF8 (RFC 6531) and are accepted by default
+	RejectUnknownTLD          bool                           // Whether to invalidate domains with an unknown TLD
+	RequireDNSSEC             bool                           // Whether to reject domains whose MX records aren't DNSSEC-validated (requires a DNSSECResolver)
+	RequireIPReverseDNS       bool                           // Whether to reject IP domains without a PTR record (requires a ReverseResolver)
+	RequireTLD                bool                           // Whether to require at least one dot and a non-empty TLD label
+	RequiredMXSuffixes        []string                       // MX host suffixes like ".google.com"; when set, domains with no MX host matching one are rejected (requires CheckDNS)
+	Resolver                  Resolver                       // Resolver for MX lookups (defaults to net.DefaultResolver)
+	ScoreWeights              ScoreWeights                   // Weights used to compute ValidationResult.Score
+	Severities                map[CheckType]Severity         // Per-check severities overriding the Reject* options, e.g. SeverityWarn for CheckTypeDisposable
+	SkipDomainSyntaxCheck     bool                           // Whether to skip the label-by-label domain syntax check (ErrInvalidDomainSyntax) and rely on the address parser alone
+	SpamtrapListURL           string                         // URL for a JSON list of spamtrap patterns
+	SpamtrapPatterns          []string                       // Spamtrap patterns matched against the local part, or the full address if they contain "@"
+	StrictRFC5321             bool                           // Whether to enforce the RFC 5321 local part, domain and path length limits
+	StripSubaddress           bool                           // Whether to remove a "+tag" subaddress from the stored Address before the spamtrap and duplicate checks
+	TLDListURL                string                         // URL for the TLD list (uses the bundled IANA list if empty)
+	TrustedDomainsURL         string                         // URL for trusted domains list
+	UseEmbeddedDisposableList bool                           // Whether to load the bundled disposable list instead of fetching the default DisposableDomainsURL
 }
 
 // DefaultOptions returns the default validator options
@@ -36,21 +102,50 @@ func DefaultOptions() Options {
 		CheckDNS:             false,
 		CheckDisposable:      false,
 		CheckFreeProvider:    false,
+		CheckTLD:             false,
 		DNSCacheTTL:          1 * time.Hour,
+		DNSNegativeCacheTTL:  5 * time.Minute,
 		DNSCacheSize:         1000,
 		DNSTimeout:           3 * time.Second,
-		DisposableDomainsURL: "https://disposable.github.io/disposable-email-domains/domains.json",
+		ListFetchRetryDelay:  500 * time.Millisecond,
+		GravatarHash:         false,
+		DisposableDomainsURL: defaultDisposableDomainsURL,
 		FreeProvidersURL:     "",
+		MaxConcurrency:       0,
 		MaxEmailLength:       254,
 		MinDomainLength:      1,
+		MinTLDLength:         0,
 		RejectDisposable:     false,
 		RejectFreeProvider:   false,
 		RejectIPDomains:      false,
 		RejectNamedEmails:    false,
 		RejectReserved:       false,
+		RejectUnknownTLD:     false,
+		RequireDNSSEC:        false,
+		RequireTLD:           false,
+		ScoreWeights:         DefaultScoreWeights(),
+		StrictRFC5321:        false,
 	}
 }
 
+// RFC 5321 length limits, in octets
+const (
+	maxLocalPartLength = 64
+	maxDomainLength    = 255
+	maxPathLength      = 254 // Full address, excluding the angle brackets of the 256-octet path
+)
+
+// ErrNonASCII is returned in ValidationResult.LastError when ASCIIOnly is set and the
+// address or domain contains non-ASCII characters
+var ErrNonASCII = errors.New("address contains non-ASCII characters")
+
+// Errors returned in ValidationResult.LastError when StrictRFC5321 is enabled
+var (
+	ErrLocalPartTooLong = errors.New("local part exceeds 64 characters")
+	ErrDomainTooLong    = errors.New("domain exceeds 255 characters")
+	ErrAddressTooLong   = errors.New("address exceeds 254 characters")
+)
+
 // DefaultFreeProviders returns the default free email providers
 func DefaultFreeProviders() map[string]struct{} {
 	return map[string]struct{}{
@@ -63,16 +158,72 @@ func DefaultFreeProviders() map[string]struct{} {
 }
 
 type ValidationResult struct {
-	Address        string        // Normalized email address
-	IsDisposable   bool          // Whether the domain is disposable
-	IsFreeProvider bool          // Whether the domain is a free provider
-	IsIPDomain     bool          // Whether the domain is an IP address
-	IsReserved     bool          // Whether the domain is reserved
-	IsValid        bool          // Whether the email is valid
-	LastError      error         // Validation error
-	Name           string        // Parsed name from email
-	Original       string        // Original email address input
-	ValidationTime time.Duration // Time taken to validate
+	Address                string        // Normalized email address
+	CanReceiveMail         bool          // Whether the domain has MX records other than a null MX (only set when CheckDNS is enabled)
+	DNSCacheHit            bool          // Whether the MX result was served from the DNS cache
+	DNSInconclusive        bool          // Whether the MX lookup failed transiently and DNSFailOpen accepted the domain
+	DNSStatus              DNSStatus     // Outcome of the MX lookup, e.g. DNSStatusNXDomain (DNSStatusNotChecked without CheckDNS)
+	DisplayNameSpoof       bool          // Whether the display name contains an address at a different domain (only with DetectDisplayNameSpoof)
+	DisplayNameSpoofDomain string        // Domain of the address in the display name, e.g. "paypal.com" (only with DetectDisplayNameSpoof)
+	DisposableConfidence   int           // Number of disposable lists that include the domain (1 for bloom filter matches)
+	DisposableSource       string        // List or MX host that flagged the domain as disposable
+	Domain                 string        // Lowercased domain used for the domain checks
+	DuplicateOf            string        // Registered address the email duplicates (see RegisterExistingAddresses)
+	Errors                 []error       // Every failed check, in order (only with CollectAllErrors; LastError is the first)
+	GravatarHash           string        // Gravatar hash of the address (when Options.GravatarHash is set)
+	IsUTF8Address          bool          // Whether the local part is non-ASCII, so delivery requires SMTPUTF8
+	IsConfusable           bool          // Whether the domain is a lookalike of a protected domain
+	HasSPF                 bool          // Whether the domain publishes an SPF record (only set with CheckSPFExists)
+	HasDMARC               bool          // Whether the domain publishes a DMARC record (only set with CheckDMARC)
+	HasMX                  bool          // Whether the MX lookup succeeded (only set when CheckDNS is enabled)
+	IsDNSSECValidated      bool          // Whether the MX records were DNSSEC-validated
+	IsDisposable           bool          // Whether the domain is disposable
+	IsDuplicate            bool          // Whether the email's canonical form matches a registered address
+	IsFreeProvider         bool          // Whether the domain is a free provider
+	IsIPDomain             bool          // Whether the domain is an IP address
+	IPReverseDNS           string        // PTR name of an IP domain (only with RequireIPReverseDNS)
+	IsPublicSuffix         bool          // Whether the domain is exactly a public suffix, so no one can register it
+	IsReserved             bool          // Whether the domain is reserved
+	IsSpamtrap             bool          // Whether the address matches a known spamtrap pattern
+	IsValidTLD             bool          // Whether the domain has a known TLD
+	IsValid                bool          // Whether the email is valid
+	LastError              error         // Validation error
+	MXRecords              []string      // MX hosts in preference order, without trailing dots (only set when CheckDNS is enabled; empty for a null MX)
+	MailProvider           string        // Provider hosting the domain's mail detected from its MX hosts, e.g. "google", or else the RequiredMXSuffixes entry they match
+	Name                   string        // Parsed name from email
+	Original               string        // Original email address input
+	Reason                 Reason        // Machine-readable failure reason (empty when valid)
+	RegistrableDomain      string        // Domain registered under the public suffix (eTLD+1), e.g. "example.co.uk" for "mail.example.co.uk"
+	ResolvesToPrivateIP    bool          // Whether the domain has no MX records and resolves to a private address (only with RejectPrivateIPResolution)
+	Score                  float64       // Confidence score from 0 to 1 (see ScoreWeights)
+	Subaddress             string        // Tag removed from the local part by StripSubaddress, e.g. "news" for "user+news@example.com"
+	Timings                Timings       // ValidationTime broken down by phase (Parse only when validation stops before the domain checks)
+	ValidationTime         time.Duration // Time taken to validate
+	Warnings               []string      // Checks flagged with SeverityWarn, e.g. "disposable domain: tempmail.com" (the email stays valid)
+}
+
+// String returns a compact one-line summary of the result for logging, like
+// "user@example.com valid=true disposable=false free=false dns=ok cache=hit (1.2ms)".
+// Failed results also include their reason and error.
+func (vr ValidationResult) String() string {
+	var b strings.Builder
+
+	address := vr.Address
+	if address == "" {
+		address = vr.Original
+	}
+	fmt.Fprintf(&b, "%s valid=%t disposable=%t free=%t dns=%s", address, vr.IsValid, vr.IsDisposable, vr.IsFreeProvider, vr.DNSStatus)
+	if vr.DNSCacheHit {
+		b.WriteString(" cache=hit")
+	}
+	if vr.Reason != "" {
+		fmt.Fprintf(&b, " reason=%s", vr.Reason)
+	}
+	fmt.Fprintf(&b, " (%v)", vr.ValidationTime.Round(time.Microsecond))
+	if vr.LastError != nil {
+		fmt.Fprintf(&b, ": %v", vr.LastError)
+	}
+	return b.String()
 }
 
 // ErrorMessage returns the last validation error as a string if present, otherwise an empty string
@@ -84,30 +235,114 @@ func (vr ValidationResult) ErrorMessage() string {
 }
 
 type Validator struct {
-	options           Options              // Validator options
-	bloomFilter       *bloom.BloomFilter   // Bloom filter for disposable domains (optional)
-	bloomOptions      BloomOptions         // Bloom filter options
-	disposableDomains map[string]struct{}  // Disposable domains (only used for map-based validation)
-	dnsCache          map[string]dnsResult // LRUCache for DNS lookups
-	freeProviders     map[string]struct{}  // Free email providers
-	trustedDomains    map[string]struct{}  // Trusted domains
-	mu                sync.RWMutex
+	options         Options                  // Validator options
+	allowedDomains  []*regexp.Regexp         // Compiled AllowedDomainPatterns
+	resolver        Resolver                 // Resolver for DNS lookups
+	resolverScope   string                   // Name of a per-call resolver, scoping its DNS cache keys ("" for the validator's own)
+	verdicts        *lruCache[domainVerdict] // Memoized domain verdicts (only used with CacheDomainVerdicts)
+	*validatorState                          // Lists, caches and stats, shared with the validators used by ValidateWith
+}
+
+// validatorState is the part of a Validator that doesn't depend on its options, so
+// ValidateWith can share it
+type validatorState struct {
+	bannedLocalParts     map[string]struct{}            // Banned local parts, lowercased
+	bloomFilter          *bloom.BloomFilter             // Bloom filter for disposable domains (optional)
+	bloomOptions         BloomOptions                   // Bloom filter options
+	bloomSalted          []*bloom.BloomFilter           // Salted filters for additional verification attempts
+	disposableDomains    map[string]struct{}            // Disposable domains (only used for map-based validation)
+	disposableMX         map[string]struct{}            // Disposable MX hosts; "*.example.com" entries match subdomains
+	disposableSources    map[string]string              // Source each disposable domain was loaded from (map-based validation only)
+	disposableAlsoIn     map[string][]string            // Further sources of disposable domains listed by more than one source
+	disposableLists      map[string]map[string]struct{} // Domains in each disposable list at its last load (map-based validation with OnListUpdate only)
+	disposableListSizes  map[string]int                 // Number of domains in each disposable list at its last load, keyed by URL
+	disposableTrie       *suffixTrie                    // Disposable domains for subdomain matching (only with MatchSubdomains)
+	disposableUpdated    time.Time                      // When the disposable list was last loaded (see DisposableListLastUpdated)
+	dnsCache             DNSCache                       // Cache of MX lookup results
+	existingAddresses    map[string]string              // Registered addresses keyed by their canonical form
+	freeProviders        map[string]struct{}            // Free email providers
+	freeProviderBases    map[string]struct{}            // Free provider names without their suffix, for variant matching
+	freeProvidersUpdated time.Time                      // When the free providers list was last loaded
+	logger               *slog.Logger                   // Debug logger; discards everything when Options.Logger is nil
+	mxFlight             singleflight.Group             // Coalesces concurrent MX lookups of the same domain
+	mxProviders          map[string]string              // Mail provider names keyed by MX host suffix
+	protectedDomains     map[string]string              // Protected domains keyed by their confusable skeleton
+	spamtrapAddresses    []*regexp.Regexp               // Spamtrap patterns matched against the full address
+	spamtrapLocalParts   []*regexp.Regexp               // Spamtrap patterns matched against the local part
+	stats                stats                          // Cumulative counters reported by Stats
+	tlds                 map[string]struct{}            // Known top-level domains
+	trustedDomains       map[string]struct{}            // Trusted domains
+	txtCache             *lruCache[txtCacheEntry]       // Cache of TXT lookup results
+	verdictGeneration    atomic.Uint64                  // Incremented when a domain list changes to invalidate verdicts
+	done                 chan struct{}                  // Closed by Close (nothing waits on it yet)
+	closeOnce            sync.Once
+	mu                   sync.RWMutex
 }
 
 func New(options Options) (*Validator, error) {
 	options = mergeWithDefaults(options)
 
-	v := &Validator{
-		options:           options,
-		disposableDomains: make(map[string]struct{}),
-		dnsCache:          make(map[string]dnsResult),
-		freeProviders:     DefaultFreeProviders(),
-		trustedDomains:    make(map[string]struct{}),
+	v := &Validator{options: options, resolver: options.Resolver, validatorState: &validatorState{
+		disposableDomains:   make(map[string]struct{}),
+		disposableMX:        make(map[string]struct{}),
+		disposableSources:   make(map[string]string),
+		disposableAlsoIn:    make(map[string][]string),
+		disposableLists:     make(map[string]map[string]struct{}),
+		disposableListSizes: make(map[string]int),
+		dnsCache:            options.DNSCache,
+		freeProviders:       make(map[string]struct{}),
+		freeProviderBases:   make(map[string]struct{}),
+		logger:              options.Logger,
+		protectedDomains:    make(map[string]string),
+		trustedDomains:      make(map[string]struct{}),
+		done:                make(chan struct{}),
+	}}
+
+	if v.resolver == nil {
+		v.resolver = net.DefaultResolver
+	}
+
+	if v.dnsCache == nil {
+		v.dnsCache = newLRUCache[DNSCacheEntry](options.DNSCacheSize)
+	}
+	v.txtCache = newLRUCache[txtCacheEntry](options.DNSCacheSize)
+
+	if v.logger == nil {
+		v.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	if options.MatchSubdomains {
+		v.disposableTrie = newSuffixTrie()
+	}
+
+	if options.CacheDomainVerdicts {
+		v.verdicts = newLRUCache[domainVerdict](domainVerdictCacheSize)
+	}
+
+	for provider := range DefaultFreeProviders() {
+		v.addFreeProvider(provider)
+	}
+	v.RegisterMXProviderPatterns(DefaultMXProviderPatterns())
+
+	if err := v.validateOptions(options); err != nil {
+		return nil, err
+	}
+
+	allowed, err := compilePatterns(options.AllowedDomainPatterns)
+	if err != nil {
+		return nil, err
 	}
+	v.allowedDomains = allowed
 
-	// Load disposable domains if enabled
+	v.RegisterDisposableMXHosts(options.DisposableMXHosts)
+
+	// Load disposable domains if enabled, from the bundled list unless a custom URL is set
 	if options.CheckDisposable {
-		if err := v.LoadDisposableDomains(options.DisposableDomainsURL); err != nil {
+		if options.UseEmbeddedDisposableList && options.DisposableDomainsURL == defaultDisposableDomainsURL {
+			if err := v.loadEmbeddedDisposableList(); err != nil {
+				return nil, err
+			}
+		} else if err := v.LoadDisposableDomains(options.DisposableDomainsURL); err != nil {
 			return nil, fmt.Errorf("failed to load disposable domains: %v", err)
 		}
 	}
@@ -119,6 +354,14 @@ func New(options Options) (*Validator, error) {
 		}
 	}
 
+	// Load the TLD list if enabled, preferring a custom URL over the bundled list
+	if options.CheckTLD {
+		v.tlds = parseTLDList(defaultTLDList)
+		if err := v.LoadTLDs(options.TLDListURL); err != nil {
+			return nil, err
+		}
+	}
+
 	// Load trusted domains if a URL is provided
 	if options.TrustedDomainsURL != "" {
 		if err := v.LoadTrustedDomains(options.TrustedDomainsURL); err != nil {
@@ -126,9 +369,103 @@ func New(options Options) (*Validator, error) {
 		}
 	}
 
+	if err := v.RegisterSpamtrapPatterns(options.SpamtrapPatterns); err != nil {
+		return nil, err
+	}
+	if err := v.LoadSpamtrapPatterns(options.SpamtrapListURL); err != nil {
+		return nil, err
+	}
+
+	v.RegisterBannedLocalParts(options.BannedLocalParts)
+	if err := v.LoadBannedLocalParts(options.BannedLocalPartsURL); err != nil {
+		return nil, err
+	}
+
 	return v, nil
 }
 
+// validateOptions reports options that can't be used together, or that need a
+// capability the resolver doesn't have
+func (v *Validator) validateOptions(options Options) error {
+	if err := validateCheckOrder(options.CheckOrder); err != nil {
+		return err
+	}
+	if err := validateSeverities(options.Severities); err != nil {
+		return err
+	}
+
+	if options.CheckReceivable && !options.CheckDNS {
+		return fmt.Errorf("CheckReceivable requires CheckDNS")
+	}
+
+	// DNSSEC status is only known for MX lookups made through a DNSSEC-aware resolver
+	if options.RequireDNSSEC {
+		if !options.CheckDNS {
+			return fmt.Errorf("RequireDNSSEC requires CheckDNS")
+		}
+		if _, ok := v.resolver.(DNSSECResolver); !ok {
+			return fmt.Errorf("RequireDNSSEC requires a DNSSECResolver")
+		}
+	}
+
+	// SPF and DMARC are published in TXT records
+	txtChecks := []struct {
+		option  string
+		enabled bool
+	}{
+		{"CheckSPFExists", options.CheckSPFExists},
+		{"CheckDMARC", options.CheckDMARC},
+	}
+	for _, check := range txtChecks {
+		if !check.enabled {
+			continue
+		}
+		if !options.CheckDNS {
+			return fmt.Errorf("%s requires CheckDNS", check.option)
+		}
+		if _, ok := v.resolver.(TXTResolver); !ok {
+			return fmt.Errorf("%s requires a TXTResolver", check.option)
+		}
+	}
+
+	if options.MinMXRecords > 0 && !options.CheckDNS {
+		return fmt.Errorf("MinMXRecords requires CheckDNS")
+	}
+
+	if options.RejectPrivateIPResolution {
+		if !options.CheckDNS {
+			return fmt.Errorf("RejectPrivateIPResolution requires CheckDNS")
+		}
+		if _, ok := v.resolver.(HostResolver); !ok {
+			return fmt.Errorf("RejectPrivateIPResolution requires a HostResolver")
+		}
+	}
+
+	if len(options.RequiredMXSuffixes) > 0 && !options.CheckDNS {
+		return fmt.Errorf("RequiredMXSuffixes requires CheckDNS")
+	}
+
+	if options.RequireIPReverseDNS {
+		if _, ok := v.resolver.(ReverseResolver); !ok {
+			return fmt.Errorf("RequireIPReverseDNS requires a ReverseResolver")
+		}
+	}
+
+	return nil
+}
+
+// Close marks the validator as closed by closing its done channel. The validator
+// doesn't run background goroutines or hold open connections, so there is nothing
+// else to stop or flush; Close exists so callers can defer it and keep working if
+// background work is added. It is safe to call more than once, and validation still
+// works after Close.
+func (v *Validator) Close() error {
+	v.closeOnce.Do(func() {
+		close(v.done)
+	})
+	return nil
+}
+
 // mergeWithDefaults takes user options and fills in any zero values with defaults
 func mergeWithDefaults(opts Options) Options {
 	defaults := DefaultOptions()
@@ -137,24 +474,37 @@ func mergeWithDefaults(opts Options) Options {
 	if opts.DNSCacheTTL == 0 {
 		opts.DNSCacheTTL = defaults.DNSCacheTTL
 	}
+	if opts.DNSNegativeCacheTTL == 0 {
+		// Never cache failures longer than successes by default
+		opts.DNSNegativeCacheTTL = min(defaults.DNSNegativeCacheTTL, opts.DNSCacheTTL)
+	}
 	if opts.DNSCacheSize == 0 {
 		opts.DNSCacheSize = defaults.DNSCacheSize
 	}
 	if opts.DNSTimeout == 0 {
 		opts.DNSTimeout = defaults.DNSTimeout
 	}
+	if opts.ListFetchRetryDelay == 0 {
+		opts.ListFetchRetryDelay = defaults.ListFetchRetryDelay
+	}
 	if opts.MaxEmailLength == 0 {
 		opts.MaxEmailLength = defaults.MaxEmailLength
 	}
 	if opts.MinDomainLength == 0 {
 		opts.MinDomainLength = defaults.MinDomainLength
 	}
+	if len(opts.CheckOrder) == 0 {
+		opts.CheckOrder = DefaultCheckOrder()
+	}
 	if opts.DisposableDomainsURL == "" {
 		opts.DisposableDomainsURL = defaults.DisposableDomainsURL
 	}
 	if opts.FreeProvidersURL == "" {
 		opts.FreeProvidersURL = defaults.FreeProvidersURL
 	}
+	if opts.ScoreWeights == (ScoreWeights{}) {
+		opts.ScoreWeights = defaults.ScoreWeights
+	}
 
 	// Boolean flags don't need special handling as they'll have their zero value (false)
 	// unless explicitly set
@@ -167,124 +517,559 @@ func (v *Validator) IsValid(email string) bool {
 	return v.Validate(email).IsValid
 }
 
-// Validate checks a single email address
+// Validate checks a single email address. Surrounding whitespace and zero-width
+// characters are removed before parsing; Original keeps the raw input.
 func (v *Validator) Validate(email string) ValidationResult {
+	return v.finalize(v.validate(email))
+}
+
+// ValidateInto checks a single email address like Validate, but resets and fills a
+// caller-provided result instead of returning one. Its Errors, Warnings and MXRecords
+// are truncated and refilled in place, so tight loops over large lists don't allocate
+// new backing arrays for every address. Those slices alias the arrays of the previous
+// result: don't keep them (or copies of the result holding them) across calls, since
+// the next call overwrites their elements. Copy them first to keep them. They may also
+// be empty but non-nil, so check them with len.
+func (v *Validator) ValidateInto(email string, result *ValidationResult) {
+	reuse := ValidationResult{
+		Errors:    result.Errors[:0],
+		MXRecords: result.MXRecords[:0],
+		Warnings:  result.Warnings[:0],
+	}
+	*result = v.finalize(v.validateFrom(reuse, email))
+}
+
+// ValidateWithTimeout checks a single email address, giving up after timeout regardless
+// of DNSTimeout. A timed-out result is invalid and has a timeout error in LastError.
+// The validation keeps running in the background and its result is discarded.
+func (v *Validator) ValidateWithTimeout(email string, timeout time.Duration) ValidationResult {
 	start := time.Now()
-	result := ValidationResult{Original: email}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
 
-	// Quick length check before more expensive operations
-	if len(email) > v.options.MaxEmailLength {
-		result.LastError = fmt.Errorf("email exceeds maximum length of %d characters", v.options.MaxEmailLength)
-		result.ValidationTime = time.Since(start)
+	done := make(chan ValidationResult, 1)
+	go func() {
+		done <- v.Validate(email)
+	}()
+
+	select {
+	case result := <-done:
 		return result
+	case <-timer.C:
+		result := ValidationResult{
+			Original:       email,
+			LastError:      fmt.Errorf("validation timeout after %v", timeout),
+			Reason:         ReasonTimeout,
+			ValidationTime: time.Since(start),
+		}
+		v.maskResult(&result)
+		return result
+	}
+}
+
+// ValidateAddress checks an already-parsed address, such as one taken from a
+// message's To or Cc header, without parsing it again. Original is set to the
+// formatted address.
+func (v *Validator) ValidateAddress(addr *mail.Address) ValidationResult {
+	start := time.Now()
+	if addr == nil {
+		return v.finalize(ValidationResult{
+			LastError:      fmt.Errorf("invalid email format: nil address"),
+			Reason:         ReasonSyntax,
+			ValidationTime: time.Since(start),
+		})
+	}
+
+	result := ValidationResult{Original: addr.String()}
+
+	at := strings.LastIndexByte(addr.Address, '@')
+	if at <= 0 || at == len(addr.Address)-1 {
+		result.LastError = fmt.Errorf("invalid email format: missing local part or domain")
+		result.Reason = ReasonSyntax
+		result.ValidationTime = time.Since(start)
+		return v.finalize(result)
+	}
+
+	return v.finalize(v.validateParsed(result, addr, start))
+}
+
+// ValidateAddressList validates each address in a comma-separated list, such as the
+// value of a To header, and returns one result per recipient. RFC 5322 groups like
+// "Team: a@x.com, b@y.com;" are expanded into their members; the group's display
+// name is discarded and only a member's own display name counts as a Name. When the
+// list can't be parsed, a single invalid result for the whole input is returned.
+func (v *Validator) ValidateAddressList(input string) []ValidationResult {
+	start := time.Now()
+	addrs, err := mail.ParseAddressList(input)
+	if err != nil {
+		return []ValidationResult{v.finalize(ValidationResult{
+			Original:       input,
+			LastError:      fmt.Errorf("invalid address list: %v", v.maskedParseError(err)),
+			Reason:         ReasonSyntax,
+			ValidationTime: time.Since(start),
+		})}
+	}
+
+	results := make([]ValidationResult, 0, len(addrs))
+	for _, addr := range addrs {
+		results = append(results, v.ValidateAddress(addr))
+	}
+	return results
+}
+
+// ValidateDomain runs the domain checks (IP, TLD, reserved, disposable, free provider
+// and MX) on a bare domain, e.g. for domain reputation checks. Address and Name are
+// left empty. The domain must be valid as the domain part of an email address, though
+// a fully-qualified name's trailing dot is accepted and removed.
+func (v *Validator) ValidateDomain(domain string) ValidationResult {
+	start := time.Now()
+	result := ValidationResult{Original: domain}
+
+	domain = normalizeDomain(domain)
+	if v.options.NormalizeUnicode {
+		domain = norm.NFC.String(domain)
+	}
+	if domain == "" || strings.Contains(domain, "@") {
+		result.LastError = fmt.Errorf("invalid domain format: %q", result.Original)
+		result.Reason = ReasonSyntax
+		result.ValidationTime = time.Since(start)
+		return v.finalize(result)
+	}
+	// Set before the syntax checks so their errors are masked with MaskDomainInResults
+	result.Domain = domain
+
+	// Domain syntax follows the same rules as in a full address
+	if _, err := mail.ParseAddress("postmaster@" + domain); err != nil {
+		result.LastError = fmt.Errorf("invalid domain format: %v", err)
+		result.Reason = ReasonSyntax
+		result.ValidationTime = time.Since(start)
+		return v.finalize(result)
+	}
+
+	if v.options.StrictRFC5321 && len(domain) > maxDomainLength {
+		result.LastError = ErrDomainTooLong
+		result.Reason = ReasonTooLong
+		result.ValidationTime = time.Since(start)
+		return v.finalize(result)
+	}
+
+	if err := v.checkDomainSyntax(domain); err != nil {
+		result.LastError = err
+		result.Reason = ReasonSyntax
+		result.ValidationTime = time.Since(start)
+		return v.finalize(result)
+	}
+
+	if v.options.ASCIIOnly && !isASCII(domain) {
+		result.LastError = fmt.Errorf("%w: %s", ErrNonASCII, domain)
+		result.Reason = ReasonNonASCII
+		result.ValidationTime = time.Since(start)
+		return v.finalize(result)
 	}
 
+	return v.finalize(v.validateDomain(result, domain, start))
+}
+
+// finalize computes the fields derived from a completed validation result
+func (v *Validator) finalize(result ValidationResult) ValidationResult {
+	// Failures that stop validation outright, like syntax errors, are the only error
+	if v.options.CollectAllErrors && result.LastError != nil && len(result.Errors) == 0 {
+		result.Errors = append(result.Errors, result.LastError)
+	}
+	// Validation that stops before the domain checks spends all of its time parsing
+	if result.Timings == (Timings{}) {
+		result.Timings.Parse = result.ValidationTime
+	}
+	result.Score = v.score(result)
+	if v.options.GravatarHash && result.Address != "" {
+		result.GravatarHash = GravatarHash(result.Address)
+	}
+	v.stats.record(result)
+	v.maskResult(&result)
+	return result
+}
+
+// validate parses an email address and runs the validation checks in order,
+// stopping at the first rejection unless CollectAllErrors is set
+func (v *Validator) validate(email string) ValidationResult {
+	return v.validateFrom(ValidationResult{}, email)
+}
+
+// validateFrom is validate filling in result, which is empty apart from the slices
+// ValidateInto reuses
+func (v *Validator) validateFrom(result ValidationResult, email string) ValidationResult {
+	start := time.Now()
+	result.Original = email
+	email = cleanInput(email)
+
 	// Parse email address including name component
 	addr, err := mail.ParseAddress(email)
 	if err != nil {
-		result.LastError = fmt.Errorf("invalid email format: %v", err)
+		result.LastError = fmt.Errorf("invalid email format: %v", v.maskedParseError(err))
+		result.Reason = ReasonSyntax
 		result.ValidationTime = time.Since(start)
 		return result
 	}
 
+	return v.validateParsed(result, addr, start)
+}
+
+// checkRFC5321Lengths checks an address against the RFC 5321 length limits, where
+// at is the index of the "@" that separates the local part from the domain
+func checkRFC5321Lengths(address string, at int) error {
+	switch {
+	case at > maxLocalPartLength:
+		return ErrLocalPartTooLong
+	case len(address)-at-1 > maxDomainLength:
+		return ErrDomainTooLong
+	case len(address) > maxPathLength:
+		return ErrAddressTooLong
+	}
+	return nil
+}
+
+// invisibleChars removes zero-width spaces, word joiners and byte order marks, which
+// are often picked up when addresses are copied from documents and web pages. Zero-width
+// joiners are only trimmed from the ends since they're part of emoji in display names.
+var invisibleChars = strings.NewReplacer("\u200b", "", "\u2060", "", "\ufeff", "")
+
+// cleanInput strips invisible characters and surrounding ASCII whitespace from raw input
+func cleanInput(email string) string {
+	// Replace allocates even when there's nothing to replace
+	if strings.ContainsAny(email, "\u200b\u2060\ufeff") {
+		email = invisibleChars.Replace(email)
+	}
+	return strings.Trim(email, " \t\r\n\v\f\u200c\u200d")
+}
+
+// isASCII reports whether s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// fail records a failed check on a result, keeping the first failure in LastError and
+// Reason. It reports whether validation should stop, which it always should unless
+// CollectAllErrors is set.
+func (v *Validator) fail(result *ValidationResult, err error, reason Reason) bool {
+	if result.LastError == nil {
+		result.LastError = err
+		result.Reason = reason
+	}
+	if !v.options.CollectAllErrors {
+		return true
+	}
+	result.Errors = append(result.Errors, err)
+	return false
+}
+
+// validateParsed runs the checks that follow parsing on an address
+func (v *Validator) validateParsed(result ValidationResult, addr *mail.Address, start time.Time) ValidationResult {
+	// Composed and decomposed forms of the same characters look identical, so they'd
+	// otherwise be stored and compared as different addresses. The caller's address
+	// is left as it is.
+	if v.options.NormalizeUnicode && !norm.NFC.IsNormalString(addr.Address) {
+		addr = &mail.Address{Name: addr.Name, Address: norm.NFC.String(addr.Address)}
+	}
+
+	// The length limit applies to the address alone, so a long display name doesn't count
+	if len(addr.Address) > v.options.MaxEmailLength {
+		if v.fail(&result, fmt.Errorf("email exceeds maximum length of %d characters", v.options.MaxEmailLength), ReasonTooLong) {
+			result.ValidationTime = time.Since(start)
+			return result
+		}
+	}
+
 	// Store both name and address components
 	result.Name = addr.Name
 	result.Address = addr.Address
 
+	// Quoted local parts may contain "@", so the domain follows the last one
+	at := strings.LastIndexByte(addr.Address, '@')
+
+	// Legacy systems may not handle UTF-8 anywhere in the address. The display name is
+	// exempt since it isn't part of the address.
+	if v.options.ASCIIOnly && !isASCII(addr.Address) {
+		err := fmt.Errorf("%w in local part: %s", ErrNonASCII, v.maskedLocalPart(addr.Address[:at]))
+		if isASCII(addr.Address[:at]) {
+			err = fmt.Errorf("%w in domain: %s", ErrNonASCII, addr.Address[at+1:])
+		}
+		if v.fail(&result, err, ReasonNonASCII) {
+			result.ValidationTime = time.Since(start)
+			return result
+		}
+	}
+
+	// Only a display name (or a comment, which the parser uses as one) makes an address
+	// named, so a bare "<user@example.com>" isn't rejected
 	if v.options.RejectNamedEmails {
-		if result.Address != email {
-			result.LastError = fmt.Errorf("named email addresses are not allowed")
+		if addr.Name != "" {
+			if v.fail(&result, fmt.Errorf("named email addresses are not allowed"), ReasonNamed) {
+				result.ValidationTime = time.Since(start)
+				return result
+			}
+		}
+	}
+
+	// Domains are case-insensitive, so every domain check uses the lowercased form
+	domain := strings.ToLower(addr.Address[at+1:])
+	result.Domain = domain
+
+	if err := v.checkDomainSyntax(domain); err != nil {
+		if v.fail(&result, err, ReasonSyntax) {
 			result.ValidationTime = time.Since(start)
 			return result
 		}
 	}
 
-	parts := strings.Split(addr.Address, "@")
-	domain := parts[1]
+	// A display name holding an address at another domain is a common phishing trick
+	if v.options.DetectDisplayNameSpoof && addr.Name != "" {
+		if spoofed := displayNameSpoofDomain(addr.Name, domain); spoofed != "" {
+			result.DisplayNameSpoof = true
+			result.DisplayNameSpoofDomain = spoofed
+			if v.options.RejectDisplayNameSpoof {
+				if v.fail(&result, fmt.Errorf("%w: %s", ErrDisplayNameSpoof, spoofed), ReasonDisplayNameSpoof) {
+					result.ValidationTime = time.Since(start)
+					return result
+				}
+			}
+		}
+	}
 
-	// Check for minimum domain length
-	if len(domain) < v.options.MinDomainLength {
-		result.LastError = fmt.Errorf("domain must be at least %d characters", v.options.MinDomainLength)
-		result.ValidationTime = time.Since(start)
-		return result
+	if v.options.StrictRFC5321 {
+		if err := checkRFC5321Lengths(addr.Address, at); err != nil {
+			if v.fail(&result, err, ReasonTooLong) {
+				result.ValidationTime = time.Since(start)
+				return result
+			}
+		}
+	}
+
+	// Non-ASCII local parts can only be delivered over SMTPUTF8. Non-ASCII domains
+	// don't need it on their own since they can be sent as punycode A-labels.
+	if !isASCII(addr.Address[:at]) {
+		result.IsUTF8Address = true
+		if v.options.RejectUTF8LocalPart {
+			if v.fail(&result, fmt.Errorf("non-ASCII local part requires SMTPUTF8: %s", v.maskedLocalPart(addr.Address[:at])), ReasonUTF8LocalPart) {
+				result.ValidationTime = time.Since(start)
+				return result
+			}
+		}
 	}
 
-	// Check for IP address domains
-	if v.isIPDomain(domain) {
-		result.IsIPDomain = true
-		if v.options.RejectIPDomains {
-			result.LastError = fmt.Errorf("IP address domains are not allowed")
+	// Banned local parts are matched with and without their subaddress
+	if v.isBannedLocalPart(addr.Address[:at]) {
+		if v.fail(&result, fmt.Errorf("%w: %s", ErrBannedLocalPart, v.maskedLocalPart(addr.Address[:at])), ReasonBannedLocalPart) {
 			result.ValidationTime = time.Since(start)
 			return result
 		}
 	}
 
-	// Check if domain is reserved
-	if v.isReserved(domain) {
-		result.IsReserved = true
-		if v.options.RejectReserved {
-			result.LastError = fmt.Errorf("reserved domain: %s", domain)
-			result.ValidationTime = time.Since(start)
+	// Only the domain is normalized; the local part is technically case-sensitive
+	if !v.options.PreserveDomainCase && addr.Address[at+1:] != domain {
+		result.Address = addr.Address[:at+1] + domain
+	}
+
+	// The checks from here on see the base mailbox, so "user+tag@" and "user@" match
+	// the same spamtraps. The domain checks don't depend on the local part.
+	if v.options.StripSubaddress {
+		result.Address, result.Subaddress, at = stripSubaddress(result.Address, at)
+	}
+
+	result.IsSpamtrap = v.isSpamtrap(result.Address, at)
+	result.DuplicateOf, result.IsDuplicate = v.duplicateOf(result.Address)
+
+	return v.validateDomain(result, domain, start)
+}
+
+// validateDomain runs the checks on the lowercased domain of an address, shared by
+// Validate and ValidateDomain
+func (v *Validator) validateDomain(result ValidationResult, domain string, start time.Time) ValidationResult {
+	listStart := time.Now()
+	result.Timings.Parse = listStart.Sub(start)
+	verdict := v.verdictFor(domain)
+
+	// Check for minimum domain length
+	if len(domain) < v.options.MinDomainLength {
+		if v.fail(&result, fmt.Errorf("domain must be at least %d characters", v.options.MinDomainLength), ReasonDomainTooShort) {
+			stopTimers(&result, start, listStart)
 			return result
 		}
 	}
 
-	// Check if domain is disposable
-	if v.isDisposable(domain) {
-		result.IsDisposable = true
-		if v.options.RejectDisposable {
-			result.LastError = fmt.Errorf("disposable domain: %s", domain)
-			result.ValidationTime = time.Since(start)
+	// Very deep subdomains are a rare abuse and typo signal
+	if v.options.MaxDomainLabels > 0 {
+		if err := checkLabelCount(domain, v.options.MaxDomainLabels); err != nil {
+			if v.fail(&result, err, ReasonTooManyLabels) {
+				stopTimers(&result, start, listStart)
+				return result
+			}
+		}
+	}
+
+	if !v.isAllowedDomain(domain) {
+		if v.fail(&result, fmt.Errorf("%w: %s", ErrDomainNotAllowed, domain), ReasonDomainNotAllowed) {
+			stopTimers(&result, start, listStart)
 			return result
 		}
 	}
 
-	if v.isFreeProvider(domain) {
-		result.IsFreeProvider = true
-		if v.options.RejectFreeProvider {
-			result.LastError = fmt.Errorf("free email provider: %s", domain)
-			result.ValidationTime = time.Since(start)
+	// Malformed or deceptive encodings can make a domain look like another one
+	if v.options.RejectInvalidIDN {
+		if err := checkIDN(domain); err != nil {
+			if v.fail(&result, err, ReasonInvalidIDN) {
+				stopTimers(&result, start, listStart)
+				return result
+			}
+		}
+	}
+
+	// Check if domain is a public suffix like "co.uk" rather than a registrable domain
+	result.RegistrableDomain = verdict.registrableDomain
+	if verdict.publicSuffix {
+		result.IsPublicSuffix = true
+		if v.options.RejectPublicSuffixDomains && !(v.options.AllowLocalhost && isLocalhost(domain)) {
+			if v.fail(&result, fmt.Errorf("domain is a public suffix: %s", domain), ReasonPublicSuffix) {
+				stopTimers(&result, start, listStart)
+				return result
+			}
+		}
+	}
+
+	// Check for a top-level domain
+	tldOK := true
+	if !verdict.ipDomain {
+		if err := v.validateTLD(domain); err != nil {
+			tldOK = false
+			if v.fail(&result, err, ReasonInvalidTLD) {
+				stopTimers(&result, start, listStart)
+				return result
+			}
+		}
+	}
+
+	// Check the TLD against the known list
+	if v.options.CheckTLD && !verdict.ipDomain && tldOK {
+		result.IsValidTLD = v.isKnownTLD(domain)
+		if !result.IsValidTLD && v.options.RejectUnknownTLD {
+			if v.fail(&result, fmt.Errorf("unknown top-level domain: %s", domain), ReasonUnknownTLD) {
+				stopTimers(&result, start, listStart)
+				return result
+			}
+		}
+	}
+
+	// Check if domain is a lookalike of a protected domain
+	result.IsConfusable = v.isConfusable(domain)
+
+	for _, check := range v.options.CheckOrder {
+		checkStart := time.Now()
+		stop := v.runCheck(check, &result, domain, verdict)
+		if check.usesDNS() {
+			result.Timings.DNS += time.Since(checkStart)
+		}
+		if stop {
+			stopTimers(&result, start, listStart)
 			return result
 		}
 	}
+	stopTimers(&result, start, listStart)
 
-	if err := v.validateMX(domain); err != nil {
-		result.LastError = fmt.Errorf("invalid domain: %v", err)
-		result.ValidationTime = time.Since(start)
-		return result
+	// Custom rules only run once every built-in check has passed
+	for _, rule := range v.options.CustomRules {
+		if result.LastError != nil {
+			break
+		}
+		if err := rule(result); err != nil {
+			v.fail(&result, err, ReasonCustomRule)
+		}
 	}
 
-	result.IsValid = true
+	result.IsValid = result.LastError == nil
 	result.ValidationTime = time.Since(start)
 	return result
 }
 
-// ValidateMany validates multiple email addresses concurrently
+// ValidateMany validates multiple email addresses concurrently. Results are in the
+// same order as the emails. With DedupInput, duplicates are validated once and each
+// copy of the result keeps its own Original.
 func (v *Validator) ValidateMany(emails []string) []ValidationResult {
 	if len(emails) == 0 {
 		return nil
 	}
 
-	resultChan := make(chan ValidationResult, len(emails))
+	if !v.options.DedupInput {
+		return v.validateConcurrently(emails)
+	}
+
+	unique, positions := dedupEmails(emails)
+	uniqueResults := v.validateConcurrently(unique)
+
+	results := make([]ValidationResult, len(emails))
+	for i, u := range positions {
+		results[i] = uniqueResults[u]
+		if !v.options.MaskInResults {
+			results[i].Original = emails[i]
+		}
+	}
+	return results
+}
+
+// validateConcurrently validates emails in parallel, up to MaxConcurrency at a time
+func (v *Validator) validateConcurrently(emails []string) []ValidationResult {
+	// Resolve each distinct domain once before validating
+	v.warmMXCache(emails)
+
+	results := make([]ValidationResult, len(emails))
 	var wg sync.WaitGroup
 
-	for _, email := range emails {
+	// Limit the number of in-flight validations when configured
+	var sem chan struct{}
+	if v.options.MaxConcurrency > 0 {
+		sem = make(chan struct{}, v.options.MaxConcurrency)
+	}
+
+	for i, email := range emails {
 		wg.Add(1)
-		go func(e string) {
+		go func(i int, e string) {
 			defer wg.Done()
-			resultChan <- v.Validate(e)
-		}(email)
+			if sem != nil {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+			}
+			results[i] = v.Validate(e)
+		}(i, email)
 	}
 
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
+	wg.Wait()
+	return results
+}
 
-	results := make([]ValidationResult, 0, len(emails))
-	for result := range resultChan {
-		results = append(results, result)
+// dedupEmails returns the distinct emails, compared after input cleanup with the
+// domain lowercased, and the index in that list of each original email
+func dedupEmails(emails []string) ([]string, []int) {
+	var unique []string
+	positions := make([]int, len(emails))
+	seen := make(map[string]int, len(emails))
+
+	for i, email := range emails {
+		key := cleanInput(email)
+		if at := strings.LastIndex(key, "@"); at >= 0 {
+			key = key[:at+1] + strings.ToLower(key[at+1:])
+		}
+
+		u, ok := seen[key]
+		if !ok {
+			u = len(unique)
+			seen[key] = u
+			unique = append(unique, email)
+		}
+		positions[i] = u
 	}
 
-	return results
+	return unique, positions
 }
@@ -1,9 +1,21 @@
 package mailcop_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -112,6 +124,16 @@ func TestValidate(t *testing.T) {
 				LastError: assert.AnError,
 			},
 		},
+		{
+			name:  "long display name with short address",
+			email: strings.Repeat("Name", 75) + " <user@example.com>",
+			expected: mailcop.ValidationResult{
+				Name:     strings.Repeat("Name", 75),
+				Address:  "user@example.com",
+				Original: strings.Repeat("Name", 75) + " <user@example.com>",
+				IsValid:  true,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -218,6 +240,45 @@ func TestValidatorOptions(t *testing.T) {
 	}
 }
 
+func TestValidateManyDedup(t *testing.T) {
+	emails := []string{
+		"user@company.org",
+		"invalid@",
+		" user@COMPANY.org",
+		"other@company.org",
+		"user@company.org",
+		"invalid@",
+	}
+
+	opts := mailcop.DefaultOptions()
+	opts.DedupInput = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	results := v.ValidateMany(emails)
+	require.Len(t, results, len(emails))
+	assert.Equal(t, int64(3), v.Stats().Validated)
+
+	for i, result := range results {
+		assert.Equal(t, emails[i], result.Original)
+		assert.Equal(t, emails[i] != "invalid@", result.IsValid)
+	}
+	assert.Equal(t, "other@company.org", results[3].Address)
+
+	t.Run("without dedup", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.DefaultOptions())
+		require.NoError(t, err)
+
+		results := v.ValidateMany(emails)
+		require.Len(t, results, len(emails))
+		assert.Equal(t, int64(len(emails)), v.Stats().Validated)
+		for i, result := range results {
+			assert.Equal(t, emails[i], result.Original)
+		}
+	})
+}
+
 func TestValidateMany(t *testing.T) {
 	opts := mailcop.DefaultOptions()
 	opts.CheckDNS = false
@@ -235,38 +296,33 @@ func TestValidateMany(t *testing.T) {
 	}
 
 	results := v.ValidateMany(emails)
-	assert.Len(t, results, len(emails))
+	require.Len(t, results, len(emails))
 
-	// Create maps to track expected results
-	found := make(map[string]bool)
-	validCount := 0
-	nameFound := false
+	// Results line up with the input
+	assert.True(t, results[0].IsValid)
+	assert.Empty(t, results[0].Name)
+	assert.False(t, results[1].IsValid)
+	assert.Error(t, results[1].LastError)
+	assert.True(t, results[2].IsValid)
+	assert.Equal(t, "John Doe", results[2].Name)
+	assert.Equal(t, "john@example.com", results[2].Address)
 
-	// Check each result without assuming order
-	for _, result := range results {
-		found[result.Original] = true
-
-		switch result.Original {
-		case "valid@example.com":
-			assert.True(t, result.IsValid)
-			assert.Empty(t, result.Name)
-			validCount++
-		case "invalid@":
-			assert.False(t, result.IsValid)
-			assert.Error(t, result.LastError)
-		case `"John Doe" <john@example.com>`:
-			assert.True(t, result.IsValid)
-			assert.Equal(t, "John Doe", result.Name)
-			assert.Equal(t, "john@example.com", result.Address)
-			validCount++
-			nameFound = true
+	t.Run("order is preserved under concurrency", func(t *testing.T) {
+		emails := make([]string, 200)
+		for i := range emails {
+			emails[i] = fmt.Sprintf("user%d@example.com", i)
 		}
-	}
 
-	// Verify we found all expected emails
-	assert.Equal(t, len(emails), len(found))
-	assert.Equal(t, 2, validCount)
-	assert.True(t, nameFound)
+		for _, limit := range []int{0, 3} {
+			opts.MaxConcurrency = limit
+			v, err := mailcop.New(opts)
+			require.NoError(t, err)
+
+			for i, result := range v.ValidateMany(emails) {
+				assert.Equal(t, emails[i], result.Original)
+			}
+		}
+	})
 }
 
 // Helper function to create long email addresses for testing
@@ -312,6 +368,495 @@ func TestLoadProviderLists(t *testing.T) {
 	assert.True(t, result.IsFreeProvider)
 }
 
+func TestListFetchRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int // Status of the failing responses
+		failures  int // Responses that fail before the list is served
+		retries   int
+		wantErr   bool
+		wantCalls int32
+	}{
+		{name: "recovers from 5xx", status: http.StatusServiceUnavailable, failures: 2, retries: 3, wantCalls: 3},
+		{name: "gives up after retries", status: http.StatusBadGateway, failures: 5, retries: 2, wantErr: true, wantCalls: 3},
+		{name: "no retries by default", status: http.StatusInternalServerError, failures: 1, wantErr: true, wantCalls: 1},
+		{name: "4xx fails fast", status: http.StatusNotFound, failures: 1, retries: 3, wantErr: true, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(calls.Add(1)) <= tt.failures {
+					w.WriteHeader(tt.status)
+					return
+				}
+				_, _ = w.Write([]byte(`["mailinator.com"]`))
+			}))
+			defer server.Close()
+
+			opts := mailcop.DefaultOptions()
+			opts.CheckDisposable = true
+			opts.DisposableDomainsURL = server.URL
+			opts.ListFetchRetries = tt.retries
+			opts.ListFetchRetryDelay = time.Millisecond
+
+			v, err := mailcop.New(opts)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "unexpected status")
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, v.IsDisposableDomain("mailinator.com"))
+		})
+	}
+}
+
+func TestLoadProviderListFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "flat array", data: `["mailinator.com", "yopmail.com"]`},
+		{name: "object keyed by domain", data: `{"mailinator.com": true, "yopmail.com": {"source": "upstream"}}`},
+		{name: "domains field", data: ` {"version": 2, "domains": ["mailinator.com", "yopmail.com"]}`},
+		{name: "empty array", data: `[]`},
+		{name: "string", data: `"mailinator.com"`, wantErr: true},
+		{name: "malformed", data: `{"domains": [`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "disposable.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.data), 0644))
+
+			opts := mailcop.DefaultOptions()
+			opts.CheckDisposable = true
+			opts.DisposableDomainsURL = "file://" + path
+
+			v, err := mailcop.New(opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			wantDisposable := tt.data != `[]`
+			assert.Equal(t, wantDisposable, v.Validate("user@mailinator.com").IsDisposable)
+			assert.Equal(t, wantDisposable, v.Validate("user@yopmail.com").IsDisposable)
+			assert.False(t, v.Validate("user@company.org").IsDisposable)
+		})
+	}
+}
+
+func TestListLastUpdated(t *testing.T) {
+	modified := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/disposable.json" {
+			w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		}
+		_, _ = w.Write([]byte(`["provider.com"]`))
+	}))
+	defer server.Close()
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.CheckFreeProvider = true
+	opts.DisposableDomainsURL = server.URL + "/disposable.json"
+	opts.FreeProvidersURL = server.URL + "/free.json"
+
+	before := time.Now()
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	assert.True(t, v.DisposableListLastUpdated().Equal(modified))
+	assert.False(t, v.FreeProvidersLastUpdated().Before(before), "falls back to the fetch time")
+
+	t.Run("file lists use the fetch time", func(t *testing.T) {
+		before := time.Now()
+		require.NoError(t, v.LoadDisposableDomains("file://"+filepath.Join("testdata", "domains.json")))
+		assert.False(t, v.DisposableListLastUpdated().Before(before))
+	})
+
+	t.Run("failed loads keep the previous time", func(t *testing.T) {
+		updated := v.FreeProvidersLastUpdated()
+		require.Error(t, v.LoadFreeProviders("file:///nonexistent.json"))
+		assert.Equal(t, updated, v.FreeProvidersLastUpdated())
+	})
+
+	t.Run("zero before loading", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.DefaultOptions())
+		require.NoError(t, err)
+		assert.True(t, v.DisposableListLastUpdated().IsZero())
+		assert.True(t, v.FreeProvidersLastUpdated().IsZero())
+	})
+}
+
+func TestListEntryNormalization(t *testing.T) {
+	messy := `[" Gmail.COM ", "mailto:Yahoo.com.", "", "   ", "MAILTO: Outlook.com", "\tproton.me\n"]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(messy))
+	}))
+	defer server.Close()
+
+	domains := []string{"gmail.com", "yahoo.com", "outlook.com", "proton.me"}
+
+	t.Run("disposable domains", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.CheckDisposable = true
+		opts.DisposableDomainsURL = server.URL + "/disposable.json"
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		loaded, ok := v.DisposableDomains()
+		require.True(t, ok)
+		assert.ElementsMatch(t, domains, loaded)
+	})
+
+	t.Run("free providers", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.CheckFreeProvider = true
+		opts.FreeProvidersURL = server.URL + "/free.json"
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		for _, domain := range domains {
+			assert.True(t, v.Validate("user@"+domain).IsFreeProvider, domain)
+		}
+		assert.False(t, v.Validate("user@company.org").IsFreeProvider)
+	})
+
+	t.Run("trusted domains", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.CheckDisposable = true
+		opts.DisposableDomainsURL = server.URL + "/disposable.json"
+		opts.TrustedDomainsURL = server.URL + "/trusted.json"
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		for _, domain := range domains {
+			assert.False(t, v.Validate("user@"+domain).IsDisposable, domain)
+		}
+	})
+
+	t.Run("bloom filter", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.CheckDisposable = true
+		opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+		require.NoError(t, v.UseBloomFilterFromSources([]string{server.URL + "/disposable.json"}, 0, mailcop.DefaultBloomOptions()))
+
+		for _, domain := range domains {
+			assert.True(t, v.IsDisposableDomain(domain), domain)
+		}
+	})
+}
+
+func TestFreeProviderMatchVariants(t *testing.T) {
+	tests := []struct {
+		email         string
+		exact         bool
+		matchVariants bool
+	}{
+		{email: "user@yahoo.com", exact: true, matchVariants: true},
+		{email: "user@yahoo.fr", exact: false, matchVariants: true},
+		{email: "user@yahoo.co.uk", exact: false, matchVariants: true},
+		{email: "user@hotmail.de", exact: false, matchVariants: true},
+		{email: "user@web.de", exact: false, matchVariants: true},
+		{email: "user@web.com.br", exact: false, matchVariants: true},
+		{email: "user@mail.yahoo.com", exact: false, matchVariants: false},
+		{email: "user@yahoo-mail.com", exact: false, matchVariants: false},
+		{email: "user@company.com", exact: false, matchVariants: false},
+	}
+
+	for _, matchVariants := range []bool{false, true} {
+		opts := mailcop.DefaultOptions()
+		opts.CheckFreeProvider = true
+		opts.FreeProviderMatchVariants = matchVariants
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+		v.RegisterFreeProviders([]string{"web.*"})
+
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s variants=%v", tt.email, matchVariants), func(t *testing.T) {
+				want := tt.exact
+				if matchVariants {
+					want = tt.matchVariants
+				}
+
+				result := v.Validate(tt.email)
+				assert.True(t, result.IsValid)
+				assert.Equal(t, want, result.IsFreeProvider)
+			})
+		}
+	}
+}
+
+func TestDisposableSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	listA := filepath.Join(tmpDir, "list_a.json")
+	listB := filepath.Join(tmpDir, "list_b.json")
+	require.NoError(t, os.WriteFile(listA, []byte(`["tempmail.com", "shared.com"]`), 0644))
+	require.NoError(t, os.WriteFile(listB, []byte(`["throwaway.com", "shared.com"]`), 0644))
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = "file://" + listA
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	require.NoError(t, v.LoadDisposableDomains("file://"+listB))
+	v.RegisterDisposableDomains([]string{"manual.com"})
+
+	tests := []struct {
+		domain string
+		source string
+	}{
+		{domain: "tempmail.com", source: "file://" + listA},
+		{domain: "throwaway.com", source: "file://" + listB},
+		{domain: "shared.com", source: "file://" + listA},
+		{domain: "manual.com", source: "registered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			source, ok := v.DisposableSource(tt.domain)
+			assert.True(t, ok)
+			assert.Equal(t, tt.source, source)
+
+			result := v.Validate("user@" + tt.domain)
+			assert.True(t, result.IsDisposable)
+			assert.Equal(t, tt.source, result.DisposableSource)
+		})
+	}
+
+	_, ok := v.DisposableSource("example.com")
+	assert.False(t, ok)
+	assert.Empty(t, v.Validate("user@company.org").DisposableSource)
+}
+
+func TestDisposableConfidence(t *testing.T) {
+	tmpDir := t.TempDir()
+	pathA := filepath.Join(tmpDir, "list_a.json")
+	pathB := filepath.Join(tmpDir, "list_b.json")
+	require.NoError(t, os.WriteFile(pathA, []byte(`["tempmail.com", "shared.com"]`), 0644))
+	require.NoError(t, os.WriteFile(pathB, []byte(`["throwaway.com", "shared.com"]`), 0644))
+	listA, listB := "file://"+pathA, "file://"+pathB
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = listA
+	opts.RejectDisposable = true
+	opts.DisposableMinSources = 2
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	require.NoError(t, v.LoadDisposableDomains(listB))
+	require.NoError(t, v.LoadDisposableDomains(listA)) // Reloading a list doesn't count twice
+	v.RegisterDisposableDomains([]string{"tempmail.com"})
+
+	tests := []struct {
+		domain         string
+		wantConfidence int
+	}{
+		{domain: "shared.com", wantConfidence: 2},
+		{domain: "tempmail.com", wantConfidence: 2},
+		{domain: "throwaway.com", wantConfidence: 1},
+		{domain: "company.org"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			result := v.Validate("user@" + tt.domain)
+			assert.Equal(t, tt.wantConfidence, result.DisposableConfidence)
+			assert.Equal(t, tt.wantConfidence > 0, result.IsDisposable)
+			assert.Equal(t, tt.wantConfidence < 2, result.IsValid)
+		})
+	}
+
+	t.Run("bloom filter", func(t *testing.T) {
+		require.NoError(t, v.UseBloomFilter(listA, mailcop.DefaultBloomOptions()))
+
+		result := v.Validate("user@shared.com")
+		assert.True(t, result.IsDisposable)
+		assert.Equal(t, 1, result.DisposableConfidence)
+	})
+}
+
+func TestDisposableDomains(t *testing.T) {
+	listPath := filepath.Join(t.TempDir(), "list.json")
+	require.NoError(t, os.WriteFile(listPath, []byte(`["tempmail.com", "throwaway.com"]`), 0644))
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = "file://" + listPath
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	v.RegisterDisposableDomains([]string{"burner.io"})
+
+	domains, ok := v.DisposableDomains()
+	require.True(t, ok)
+	assert.Equal(t, []string{"burner.io", "tempmail.com", "throwaway.com"}, domains)
+
+	// The result is a copy
+	domains[0] = "company.org"
+	assert.False(t, v.Validate("user@company.org").IsDisposable)
+
+	t.Run("bloom filter", func(t *testing.T) {
+		require.NoError(t, v.UseBloomFilter("file://"+listPath, mailcop.DefaultBloomOptions()))
+
+		domains, ok := v.DisposableDomains()
+		assert.False(t, ok)
+		assert.Nil(t, domains)
+	})
+}
+
+func TestIsDisposableDomain(t *testing.T) {
+	testDataPath := "file://" + filepath.Join("testdata", "domains.json")
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = testDataPath
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	v.RegisterTrustedDomains([]string{"throwaway.com"})
+
+	check := func(t *testing.T) {
+		assert.True(t, v.IsDisposableDomain("tempmail.com"))
+		assert.True(t, v.IsDisposableDomain(" TempMail.com "))
+		assert.False(t, v.IsDisposableDomain("company.org"))
+		assert.False(t, v.IsDisposableDomain("throwaway.com"), "trusted domains are never disposable")
+	}
+
+	t.Run("map", check)
+
+	t.Run("bloom filter", func(t *testing.T) {
+		require.NoError(t, v.UseBloomFilter(testDataPath, mailcop.DefaultBloomOptions()))
+		check(t)
+	})
+
+	t.Run("disposable checks off", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.DefaultOptions())
+		require.NoError(t, err)
+		v.RegisterDisposableDomains([]string{"tempmail.com"})
+
+		assert.False(t, v.IsDisposableDomain("tempmail.com"))
+	})
+}
+
+func TestDomainNormalization(t *testing.T) {
+	dir := t.TempDir()
+	disposablePath := filepath.Join(dir, "disposable.json")
+	require.NoError(t, os.WriteFile(disposablePath, []byte(`["TempMail.COM", "throwaway.com.", "Legit.Org"]`), 0644))
+	freePath := filepath.Join(dir, "free.json")
+	require.NoError(t, os.WriteFile(freePath, []byte(`["Gmail.Com."]`), 0644))
+	trustedPath := filepath.Join(dir, "trusted.json")
+	require.NoError(t, os.WriteFile(trustedPath, []byte(`["LEGIT.org."]`), 0644))
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.CheckFreeProvider = true
+	opts.DisposableDomainsURL = "file://" + disposablePath
+	opts.FreeProvidersURL = "file://" + freePath
+	opts.TrustedDomainsURL = "file://" + trustedPath
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	v.RegisterDisposableDomains([]string{"Burner.IO."})
+	v.RegisterFreeProviders([]string{"YAHOO.com"})
+
+	tests := []struct {
+		email          string
+		wantDisposable bool
+		wantFree       bool
+	}{
+		{email: "user@tempmail.com", wantDisposable: true},
+		{email: "user@THROWAWAY.com", wantDisposable: true},
+		{email: "user@burner.io", wantDisposable: true},
+		{email: "user@legit.org"}, // Trusted
+		{email: "user@gmail.com", wantFree: true},
+		{email: "user@Yahoo.COM", wantFree: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantDisposable, result.IsDisposable)
+			assert.Equal(t, tt.wantFree, result.IsFreeProvider)
+		})
+	}
+
+	t.Run("trailing dot input", func(t *testing.T) {
+		assert.True(t, v.IsDisposableDomain("TempMail.com."))
+		assert.False(t, v.IsDisposableDomain("legit.org."))
+
+		result := v.ValidateDomain("Gmail.com.")
+		assert.True(t, result.IsValid)
+		assert.Equal(t, "gmail.com", result.Domain)
+		assert.True(t, result.IsFreeProvider)
+	})
+
+	t.Run("bloom filter", func(t *testing.T) {
+		require.NoError(t, v.UseBloomFilter("file://"+disposablePath, mailcop.DefaultBloomOptions()))
+		v.RegisterDisposableDomains([]string{"Spam.Box."})
+
+		assert.True(t, v.IsDisposableDomain("throwaway.com"))
+		assert.True(t, v.IsDisposableDomain("spam.box."))
+		assert.False(t, v.IsDisposableDomain("legit.org"))
+	})
+}
+
+func TestConfusables(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		wantMatch bool
+	}{
+		{name: "cyrillic lookalike", email: "user@раypal.com", wantMatch: true},
+		{name: "punycode lookalike", email: "user@xn--ypal-43d9g.com", wantMatch: true},
+		{name: "greek lookalike", email: "user@gοοgle.com", wantMatch: true},
+		{name: "digit substitution", email: "user@paypa1.com", wantMatch: true},
+		{name: "diacritic", email: "user@páypal.com", wantMatch: true},
+		{name: "identical domain", email: "user@paypal.com", wantMatch: false},
+		{name: "identical domain with different case", email: "user@PayPal.com", wantMatch: false},
+		{name: "unrelated domain", email: "user@example.com", wantMatch: false},
+		{name: "subdomain of protected domain", email: "user@mail.paypal.com", wantMatch: false},
+	}
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckConfusables = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	v.RegisterProtectedDomains([]string{"paypal.com", "google.com"})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.True(t, result.IsValid)
+			assert.Equal(t, tt.wantMatch, result.IsConfusable)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.DefaultOptions())
+		require.NoError(t, err)
+		v.RegisterProtectedDomains([]string{"paypal.com"})
+
+		assert.False(t, v.Validate("user@раypal.com").IsConfusable)
+	})
+}
+
 func TestReservedDomains(t *testing.T) {
 	opts := mailcop.DefaultOptions()
 	opts.CheckDNS = false
@@ -399,40 +944,157 @@ func TestReservedDomains(t *testing.T) {
 	}
 }
 
-func TestNamedEmails(t *testing.T) {
+func TestAllowLocalhost(t *testing.T) {
 	opts := mailcop.DefaultOptions()
-	opts.CheckDNS = false
-	opts.MaxEmailLength = 254
-	opts.MinDomainLength = 3
+	opts.RejectReserved = true
+
+	prod, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	opts.AllowLocalhost = true
+	dev, err := mailcop.New(opts)
+	require.NoError(t, err)
 
 	tests := []struct {
-		name        string
-		email       string
-		rejectNamed bool
-		expected    mailcop.ValidationResult
+		email    string
+		wantDev  bool
+		wantProd bool
 	}{
-		{
-			name:        "simple display name with quotes",
-			email:       `"John Doe" <john.doe@example.com>`,
-			rejectNamed: false,
-			expected: mailcop.ValidationResult{
-				Name:     "John Doe",
-				Address:  "john.doe@example.com",
-				Original: `"John Doe" <john.doe@example.com>`,
-				IsValid:  true,
-			},
-		},
-		{
-			name:        "display name with special characters",
-			email:       `"Müller, Hans" <hans.mueller@example.com>`,
-			rejectNamed: false,
-			expected: mailcop.ValidationResult{
-				Name:     "Müller, Hans",
-				Address:  "hans.mueller@example.com",
-				Original: `"Müller, Hans" <hans.mueller@example.com>`,
-				IsValid:  true,
-			},
-		},
+		{email: "user@localhost", wantDev: true},
+		{email: "user@app.LOCALHOST", wantDev: true},
+		{email: "user@example.com"},
+		{email: "user@domain.test"},
+		{email: "user@localhost.com", wantDev: true, wantProd: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := dev.Validate(tt.email)
+			assert.Equal(t, tt.wantDev, result.IsValid)
+			assert.Equal(t, !tt.wantProd, result.IsReserved)
+
+			result = prod.Validate(tt.email)
+			assert.Equal(t, tt.wantProd, result.IsValid)
+			assert.Equal(t, !tt.wantProd, result.IsReserved)
+		})
+	}
+}
+
+func TestPublicSuffixDomains(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+
+	lenient, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	opts.RejectPublicSuffixDomains = true
+	strict, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		email      string
+		wantSuffix bool
+	}{
+		{email: "user@co.uk", wantSuffix: true},
+		{email: "user@CO.UK", wantSuffix: true},
+		{email: "user@github.io", wantSuffix: true},
+		{email: "user@com", wantSuffix: true},
+		{email: "user@bbc.co.uk"},
+		{email: "user@someone.github.io"},
+		{email: "user@example.org"},
+		{email: "user@[192.168.1.1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := lenient.Validate(tt.email)
+			assert.True(t, result.IsValid)
+			assert.Equal(t, tt.wantSuffix, result.IsPublicSuffix)
+
+			result = strict.Validate(tt.email)
+			assert.Equal(t, !tt.wantSuffix, result.IsValid)
+			assert.Equal(t, tt.wantSuffix, result.IsPublicSuffix)
+			if tt.wantSuffix {
+				assert.Equal(t, mailcop.ReasonPublicSuffix, result.Reason)
+			}
+		})
+	}
+
+	t.Run("localhost with AllowLocalhost", func(t *testing.T) {
+		opts := opts
+		opts.AllowLocalhost = true
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("user@localhost")
+		assert.True(t, result.IsValid)
+		assert.True(t, result.IsPublicSuffix)
+
+		assert.False(t, strict.Validate("user@localhost").IsValid)
+	})
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{email: "user@mail.corp.example.co.uk", want: "example.co.uk"},
+		{email: "user@Example.COM", want: "example.com"},
+		{email: "user@someone.github.io", want: "someone.github.io"},
+		{email: "user@co.uk"},
+		{email: "user@[192.168.1.1]"},
+	}
+
+	for _, cache := range []bool{false, true} {
+		opts := mailcop.DefaultOptions()
+		opts.CacheDomainVerdicts = cache
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s cached=%v", tt.email, cache), func(t *testing.T) {
+				assert.Equal(t, tt.want, v.Validate(tt.email).RegistrableDomain)
+				assert.Equal(t, tt.want, v.Validate(tt.email).RegistrableDomain)
+			})
+		}
+	}
+}
+
+func TestNamedEmails(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = false
+	opts.MaxEmailLength = 254
+	opts.MinDomainLength = 3
+
+	tests := []struct {
+		name        string
+		email       string
+		rejectNamed bool
+		expected    mailcop.ValidationResult
+	}{
+		{
+			name:        "simple display name with quotes",
+			email:       `"John Doe" <john.doe@example.com>`,
+			rejectNamed: false,
+			expected: mailcop.ValidationResult{
+				Name:     "John Doe",
+				Address:  "john.doe@example.com",
+				Original: `"John Doe" <john.doe@example.com>`,
+				IsValid:  true,
+			},
+		},
+		{
+			name:        "display name with special characters",
+			email:       `"Müller, Hans" <hans.mueller@example.com>`,
+			rejectNamed: false,
+			expected: mailcop.ValidationResult{
+				Name:     "Müller, Hans",
+				Address:  "hans.mueller@example.com",
+				Original: `"Müller, Hans" <hans.mueller@example.com>`,
+				IsValid:  true,
+			},
+		},
 		{
 			name:        "display name with escaped quotes",
 			email:       `"O\"Reilly, Bill" <bill@example.com>`,
@@ -583,6 +1245,57 @@ func TestNamedEmails(t *testing.T) {
 	}
 }
 
+func TestDisplayNameSpoof(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.DetectDisplayNameSpoof = true
+
+	detect, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	opts.RejectDisplayNameSpoof = true
+	reject, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		email      string
+		wantDomain string // Spoofed domain, empty when there's no spoof
+	}{
+		{email: `"billing@paypal.com" <scammer@evil.ru>`, wantDomain: "paypal.com"},
+		{email: `"Billing@PayPal.COM." <scammer@evil.ru>`, wantDomain: "paypal.com"},
+		{email: `"Support (help@bank.co.uk)" <help@bank-secure.net>`, wantDomain: "bank.co.uk"},
+		{email: `"support@paypal.com" <support@mail.paypal.com>`},
+		{email: `"john@example.org" <john@example.org>`},
+		{email: `John Doe <john@example.org>`},
+		{email: `"Sales @ Acme" <sales@acme.com>`},
+		{email: `scammer@evil.ru`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := detect.Validate(tt.email)
+			assert.True(t, result.IsValid)
+			assert.Equal(t, tt.wantDomain != "", result.DisplayNameSpoof)
+			assert.Equal(t, tt.wantDomain, result.DisplayNameSpoofDomain)
+
+			result = reject.Validate(tt.email)
+			assert.Equal(t, tt.wantDomain == "", result.IsValid)
+			if tt.wantDomain != "" {
+				assert.ErrorIs(t, result.LastError, mailcop.ErrDisplayNameSpoof)
+				assert.Equal(t, mailcop.ReasonDisplayNameSpoof, result.Reason)
+			}
+		})
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.DefaultOptions())
+		require.NoError(t, err)
+
+		result := v.Validate(`"billing@paypal.com" <scammer@evil.ru>`)
+		assert.True(t, result.IsValid)
+		assert.False(t, result.DisplayNameSpoof)
+	})
+}
+
 func TestIPDomains(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -709,3 +1422,1456 @@ func TestIPDomains(t *testing.T) {
 		})
 	}
 }
+
+func TestRequireTLD(t *testing.T) {
+	tests := []struct {
+		name         string
+		email        string
+		requireTLD   bool
+		minTLDLength int
+		wantValid    bool
+	}{
+		{name: "no TLD allowed by default", email: "user@gmail", wantValid: true},
+		{name: "no TLD rejected", email: "user@gmail", requireTLD: true, wantValid: false},
+		{name: "short domain with TLD", email: "user@a.b", requireTLD: true, wantValid: true},
+		{name: "regular domain", email: "user@gmail.com", requireTLD: true, wantValid: true},
+		{name: "TLD too short", email: "user@a.b", requireTLD: true, minTLDLength: 2, wantValid: false},
+		{name: "TLD meets minimum", email: "user@gmail.co", requireTLD: true, minTLDLength: 2, wantValid: true},
+		{name: "min TLD length without RequireTLD", email: "user@gmail.c", minTLDLength: 2, wantValid: false},
+		{name: "IP domain skips TLD check", email: "user@[127.0.0.1]", requireTLD: true, minTLDLength: 2, wantValid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := mailcop.DefaultOptions()
+			opts.RequireTLD = tt.requireTLD
+			opts.MinTLDLength = tt.minTLDLength
+
+			v, err := mailcop.New(opts)
+			require.NoError(t, err)
+
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantValid, result.IsValid)
+			if !tt.wantValid {
+				assert.Error(t, result.LastError)
+			}
+		})
+	}
+}
+
+func TestCheckTLD(t *testing.T) {
+	tests := []struct {
+		name         string
+		email        string
+		rejectTLD    bool
+		wantValidTLD bool
+		wantValid    bool
+	}{
+		{name: "known generic TLD", email: "user@example.com", wantValidTLD: true, wantValid: true},
+		{name: "known country TLD", email: "user@example.co.uk", wantValidTLD: true, wantValid: true},
+		{name: "known TLD uppercase", email: "user@EXAMPLE.ORG", wantValidTLD: true, wantValid: true},
+		{name: "known punycode TLD", email: "user@example.xn--p1ai", wantValidTLD: true, wantValid: true},
+		{name: "unknown TLD flagged", email: "user@foo.zzz", wantValidTLD: false, wantValid: true},
+		{name: "unknown TLD rejected", email: "user@foo.zzz", rejectTLD: true, wantValidTLD: false, wantValid: false},
+		{name: "IP domain not checked", email: "user@[127.0.0.1]", rejectTLD: true, wantValidTLD: false, wantValid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := mailcop.DefaultOptions()
+			opts.CheckTLD = true
+			opts.RejectUnknownTLD = tt.rejectTLD
+
+			v, err := mailcop.New(opts)
+			require.NoError(t, err)
+
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantValidTLD, result.IsValidTLD)
+			assert.Equal(t, tt.wantValid, result.IsValid)
+		})
+	}
+}
+
+func TestLoadTLDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tlds.txt")
+	data := "# Version 2024010100\nCOM\nZZZ\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckTLD = true
+	opts.RejectUnknownTLD = true
+	opts.TLDListURL = "file://" + path
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	assert.True(t, v.Validate("user@foo.zzz").IsValid)
+	assert.True(t, v.Validate("user@foo.com").IsValid)
+	assert.False(t, v.Validate("user@foo.org").IsValid, "custom list replaces the bundled list")
+
+	opts.TLDListURL = "file://" + filepath.Join(t.TempDir(), "missing.txt")
+	_, err = mailcop.New(opts)
+	assert.Error(t, err)
+}
+
+func TestScore(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.CheckFreeProvider = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	// Without DNS, weights are Format .35, NotDisposable .20, NotFreeProvider .05,
+	// NotIPDomain .05 and NotReserved .10, for a total of .75
+	tests := []struct {
+		name      string
+		email     string
+		wantScore float64
+	}{
+		{name: "invalid format", email: "invalid@", wantScore: 0},
+		{name: "clean domain", email: "user@company.org", wantScore: 1},
+		{name: "free provider", email: "user@gmail.com", wantScore: 0.70 / 0.75},
+		{name: "disposable", email: "user@tempmail.com", wantScore: 0.55 / 0.75},
+		{name: "reserved", email: "user@example.com", wantScore: 0.65 / 0.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.InDelta(t, tt.wantScore, result.Score, 1e-9)
+			assert.Equal(t, result.Score, v.Validate(tt.email).Score, "score should be deterministic")
+		})
+	}
+
+	t.Run("custom weights", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.CheckFreeProvider = true
+		opts.ScoreWeights = mailcop.ScoreWeights{Format: 1, NotFreeProvider: 3}
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		assert.InDelta(t, 0.25, v.Validate("user@gmail.com").Score, 1e-9)
+		assert.InDelta(t, 1.0, v.Validate("user@company.org").Score, 1e-9)
+	})
+}
+
+// net/mail unquotes the local part, so the parsed address contains multiple "@"
+func TestQuotedLocalParts(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.RejectReserved = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		email       string
+		wantAddress string
+		wantValid   bool
+	}{
+		{
+			name:        "quoted local part with embedded @",
+			email:       `"a@b"@company.org`,
+			wantAddress: "a@b@company.org",
+			wantValid:   true,
+		},
+		{
+			name:        "quoted local part with embedded reserved domain",
+			email:       `"user@example.com"@company.org`,
+			wantAddress: "user@example.com@company.org",
+			wantValid:   true,
+		},
+		{
+			name:        "quoted local part on reserved domain",
+			email:       `"a@company.org"@example.com`,
+			wantAddress: "a@company.org@example.com",
+			wantValid:   false,
+		},
+		{
+			name:        "named address with quoted local part",
+			email:       `John <"john@home"@company.org>`,
+			wantAddress: "john@home@company.org",
+			wantValid:   true,
+		},
+		{
+			name:        "address with comment",
+			email:       `user@company.org (Work Account)`,
+			wantAddress: "user@company.org",
+			wantValid:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantAddress, result.Address)
+			assert.Equal(t, tt.wantValid, result.IsValid)
+		})
+	}
+}
+
+func TestPreserveDomainCase(t *testing.T) {
+	tests := []struct {
+		name        string
+		preserve    bool
+		email       string
+		wantAddress string
+	}{
+		{
+			name:        "domain lowercased, local part preserved",
+			email:       "John.Doe@Company.ORG",
+			wantAddress: "John.Doe@company.org",
+		},
+		{
+			name:        "named address",
+			email:       "John <John@Company.Org>",
+			wantAddress: "John@company.org",
+		},
+		{
+			name:        "domain case preserved",
+			preserve:    true,
+			email:       "John.Doe@Company.ORG",
+			wantAddress: "John.Doe@Company.ORG",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := mailcop.DefaultOptions()
+			opts.PreserveDomainCase = tt.preserve
+
+			v, err := mailcop.New(opts)
+			require.NoError(t, err)
+
+			result := v.Validate(tt.email)
+			assert.True(t, result.IsValid)
+			assert.Equal(t, tt.wantAddress, result.Address)
+		})
+	}
+
+	t.Run("zero options lowercase the domain", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.Options{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "John.Doe@company.org", v.Validate("John.Doe@Company.ORG").Address)
+	})
+
+	t.Run("domain checks ignore case", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.PreserveDomainCase = true
+		opts.CheckDisposable = true
+		opts.CheckFreeProvider = true
+		opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		assert.True(t, v.Validate("user@TempMail.com").IsDisposable)
+		assert.True(t, v.Validate("user@GMAIL.com").IsFreeProvider)
+		assert.True(t, v.Validate("user@Example.COM").IsReserved)
+	})
+}
+
+func TestResultDomain(t *testing.T) {
+	tests := []struct {
+		email      string
+		wantDomain string
+	}{
+		{email: "user@company.org", wantDomain: "company.org"},
+		{email: "User@Mail.Company.ORG", wantDomain: "mail.company.org"},
+		{email: "John Doe <john@Company.org>", wantDomain: "company.org"},
+		{email: `"a@b"@company.org`, wantDomain: "company.org"},
+		{email: "user@[192.168.1.1]", wantDomain: "[192.168.1.1]"},
+		{email: "invalid@", wantDomain: ""},
+	}
+
+	v, err := mailcop.New(mailcop.DefaultOptions())
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			assert.Equal(t, tt.wantDomain, v.Validate(tt.email).Domain)
+		})
+	}
+
+	assert.Equal(t, "company.org", v.ValidateAddress(&mail.Address{Address: "user@COMPANY.org"}).Domain)
+}
+
+func TestClose(t *testing.T) {
+	v, err := mailcop.New(mailcop.DefaultOptions())
+	require.NoError(t, err)
+
+	assert.NoError(t, v.Close())
+	assert.NoError(t, v.Close(), "Close should be idempotent")
+
+	assert.True(t, v.Validate("user@company.org").IsValid, "validation should still work after Close")
+}
+
+func TestGravatarHash(t *testing.T) {
+	// Example from the Gravatar documentation
+	const want = "0bc83cb571cd1c50ba6f3e8a78ef1346"
+
+	assert.Equal(t, want, mailcop.GravatarHash("MyEmailAddress@example.com "))
+	assert.Equal(t, want, mailcop.GravatarHash("myemailaddress@example.com"))
+
+	opts := mailcop.DefaultOptions()
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	assert.Empty(t, v.Validate("MyEmailAddress@example.com").GravatarHash)
+
+	opts.GravatarHash = true
+	v, err = mailcop.New(opts)
+	require.NoError(t, err)
+	assert.Equal(t, want, v.Validate("My Name <MyEmailAddress@example.com>").GravatarHash)
+	assert.Empty(t, v.Validate("invalid@").GravatarHash)
+}
+
+func TestValidateAddress(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.RejectReserved = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	t.Run("addresses from a header", func(t *testing.T) {
+		addrs, err := mail.ParseAddressList(`John Doe <john@company.org>, jane@example.com`)
+		require.NoError(t, err)
+		require.Len(t, addrs, 2)
+
+		result := v.ValidateAddress(addrs[0])
+		assert.True(t, result.IsValid)
+		assert.Equal(t, "John Doe", result.Name)
+		assert.Equal(t, "john@company.org", result.Address)
+		assert.Equal(t, `"John Doe" <john@company.org>`, result.Original)
+
+		result = v.ValidateAddress(addrs[1])
+		assert.False(t, result.IsValid)
+		assert.True(t, result.IsReserved)
+	})
+
+	t.Run("named rejection uses the name", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.RejectNamedEmails = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		assert.False(t, v.ValidateAddress(&mail.Address{Name: "John", Address: "john@company.org"}).IsValid)
+		assert.True(t, v.ValidateAddress(&mail.Address{Address: "john@company.org"}).IsValid)
+	})
+
+	t.Run("malformed addresses", func(t *testing.T) {
+		assert.Error(t, v.ValidateAddress(nil).LastError)
+		assert.Error(t, v.ValidateAddress(&mail.Address{Address: "no-at-sign"}).LastError)
+		assert.Error(t, v.ValidateAddress(&mail.Address{Address: "user@"}).LastError)
+		assert.Error(t, v.ValidateAddress(&mail.Address{Address: createLongEmail(300)}).LastError)
+	})
+}
+
+func TestInputCleanup(t *testing.T) {
+	v, err := mailcop.New(mailcop.DefaultOptions())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		input     string
+		wantValid bool
+		wantAddr  string
+	}{
+		{name: "surrounding whitespace", input: " user@company.org\n", wantValid: true, wantAddr: "user@company.org"},
+		{name: "tabs and carriage return", input: "\tuser@company.org\r\n", wantValid: true, wantAddr: "user@company.org"},
+		{name: "zero-width spaces", input: "\u200buser@\u200bcompany.org\u200b", wantValid: true, wantAddr: "user@company.org"},
+		{name: "byte order mark", input: "\ufeffuser@company.org", wantValid: true, wantAddr: "user@company.org"},
+		{name: "trailing zero-width joiner", input: "user@company.org\u200d", wantValid: true, wantAddr: "user@company.org"},
+		{name: "named address", input: " John <user@company.org> ", wantValid: true, wantAddr: "user@company.org"},
+		{name: "inner space", input: "user @company.org", wantValid: false},
+		{name: "only whitespace", input: " \u200b\n", wantValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.Validate(tt.input)
+			assert.Equal(t, tt.wantValid, result.IsValid)
+			assert.Equal(t, tt.wantAddr, result.Address)
+			assert.Equal(t, tt.input, result.Original)
+		})
+	}
+
+	t.Run("cleaned bare address isn't named", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.RejectNamedEmails = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		assert.True(t, v.Validate(" user@company.org\n").IsValid)
+		assert.False(t, v.Validate(" John <user@company.org>").IsValid)
+	})
+
+	t.Run("named means having a display name", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.RejectNamedEmails = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		for input, named := range map[string]bool{
+			"<user@company.org>":      false,
+			" <user@company.org> ":    false,
+			" user@company.org ":      false,
+			`"" <user@company.org>`:   false,
+			"John <user@company.org>": true,
+			"user@company.org (John)": true,
+		} {
+			result := v.Validate(input)
+			assert.Equal(t, !named, result.IsValid, input)
+			if named {
+				assert.Equal(t, mailcop.ReasonNamed, result.Reason, input)
+			}
+		}
+	})
+}
+
+func TestValidateAddressList(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.RejectReserved = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		input         string
+		wantAddresses []string
+		wantNames     []string
+		wantValid     []bool
+	}{
+		{
+			name:          "plain list",
+			input:         "john@company.org, Jane Doe <jane@company.org>",
+			wantAddresses: []string{"john@company.org", "jane@company.org"},
+			wantNames:     []string{"", "Jane Doe"},
+			wantValid:     []bool{true, true},
+		},
+		{
+			name:          "group",
+			input:         "Team: john@company.org, jane@example.com;",
+			wantAddresses: []string{"john@company.org", "jane@example.com"},
+			wantNames:     []string{"", ""},
+			wantValid:     []bool{true, false},
+		},
+		{
+			name:          "group and address",
+			input:         "Team: Jane <jane@company.org>;, bob@company.org",
+			wantAddresses: []string{"jane@company.org", "bob@company.org"},
+			wantNames:     []string{"Jane", ""},
+			wantValid:     []bool{true, true},
+		},
+		{
+			name:  "empty group",
+			input: "Undisclosed recipients:;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := v.ValidateAddressList(tt.input)
+			require.Len(t, results, len(tt.wantAddresses))
+			for i, result := range results {
+				assert.Equal(t, tt.wantAddresses[i], result.Address)
+				assert.Equal(t, tt.wantNames[i], result.Name)
+				assert.Equal(t, tt.wantValid[i], result.IsValid)
+			}
+		})
+	}
+
+	t.Run("unparseable list", func(t *testing.T) {
+		for _, input := range []string{"", "not-an-address, john@company.org"} {
+			results := v.ValidateAddressList(input)
+			require.Len(t, results, 1)
+			assert.False(t, results[0].IsValid)
+			assert.Error(t, results[0].LastError)
+			assert.Equal(t, input, results[0].Original)
+		}
+	})
+}
+
+func TestDisposableMXHosts(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+	opts.DisposableMXHosts = []string{"mx.mailinator.com", "*.trashmail.net"}
+	opts.Resolver = staticResolver{
+		"fresh-burner.com":  {"MX.Mailinator.com."},
+		"rotating.org":      {"backup.company.org.", "in1.pool.trashmail.net."},
+		"company.org":       {"mx.company.org."},
+		"trusted-burner.io": {"mx.mailinator.com."},
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	v.RegisterTrustedDomains([]string{"trusted-burner.io"})
+
+	tests := []struct {
+		email          string
+		wantDisposable bool
+	}{
+		{email: "user@fresh-burner.com", wantDisposable: true},
+		{email: "user@rotating.org", wantDisposable: true},
+		{email: "user@company.org", wantDisposable: false},
+		{email: "user@trusted-burner.io", wantDisposable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.True(t, result.HasMX)
+			assert.Equal(t, tt.wantDisposable, result.IsDisposable)
+			assert.Equal(t, tt.wantDisposable, result.DisposableSource != "")
+			assert.True(t, result.IsValid)
+		})
+	}
+
+	t.Run("rejected when RejectDisposable is set", func(t *testing.T) {
+		opts := opts
+		opts.RejectDisposable = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("user@fresh-burner.com")
+		assert.False(t, result.IsValid)
+		assert.True(t, result.IsDisposable)
+		assert.Equal(t, "mx:mx.mailinator.com", result.DisposableSource)
+		assert.Error(t, result.LastError)
+	})
+
+	t.Run("registered after creation", func(t *testing.T) {
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		assert.False(t, v.Validate("user@company.org").IsDisposable)
+		v.RegisterDisposableMXHosts([]string{"*.company.org"})
+		assert.True(t, v.Validate("user@company.org").IsDisposable)
+	})
+}
+
+func TestValidateWithTimeout(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.DNSTimeout = 5 * time.Second
+	opts.Resolver = slowResolver{delay: 200 * time.Millisecond}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	t.Run("timeout exceeded", func(t *testing.T) {
+		start := time.Now()
+		result := v.ValidateWithTimeout("user@slow.com", 20*time.Millisecond)
+		assert.Less(t, time.Since(start), 150*time.Millisecond)
+		assert.False(t, result.IsValid)
+		assert.Equal(t, "user@slow.com", result.Original)
+		assert.ErrorContains(t, result.LastError, "timeout")
+	})
+
+	t.Run("completes within timeout", func(t *testing.T) {
+		result := v.ValidateWithTimeout("user@fast.com", 2*time.Second)
+		assert.True(t, result.IsValid)
+		assert.NoError(t, result.LastError)
+	})
+}
+
+func TestUTF8LocalPart(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		wantUTF8  bool
+		wantValid bool // With RejectUTF8LocalPart set
+	}{
+		{name: "chinese local part", email: "用户@example.com", wantUTF8: true, wantValid: false},
+		{name: "accented local part", email: "Jöhn <jöhn@example.com>", wantUTF8: true, wantValid: false},
+		{name: "quoted utf8 local part", email: `"用户"@example.com`, wantUTF8: true, wantValid: false},
+		{name: "ascii local part with idn domain", email: "user@例え.jp", wantUTF8: false, wantValid: true},
+		{name: "ascii address", email: "user@example.com", wantUTF8: false, wantValid: true},
+	}
+
+	// The zero value accepts them, as DefaultOptions does
+	allowed, err := mailcop.New(mailcop.Options{})
+	require.NoError(t, err)
+
+	opts := mailcop.DefaultOptions()
+	opts.RejectUTF8LocalPart = true
+	strict, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := allowed.Validate(tt.email)
+			assert.True(t, result.IsValid)
+			assert.Equal(t, tt.wantUTF8, result.IsUTF8Address)
+
+			result = strict.Validate(tt.email)
+			assert.Equal(t, tt.wantValid, result.IsValid)
+			assert.Equal(t, tt.wantUTF8, result.IsUTF8Address)
+			if !tt.wantValid {
+				assert.ErrorContains(t, result.LastError, "SMTPUTF8")
+			}
+		})
+	}
+}
+
+func TestASCIIOnly(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.ASCIIOnly = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := map[string]string{ // Email to the error, empty when valid
+		"user@example.com":                 "",
+		`"José García" <jose@example.com>`: "", // Only the address has to be ASCII
+		"user@xn--mnchen-3ya.de":           "",
+		"josé@example.com":                 "address contains non-ASCII characters in local part: josé",
+		"user@münchen.de":                  "address contains non-ASCII characters in domain: münchen.de",
+	}
+	for email, wantErr := range tests {
+		result := v.Validate(email)
+		assert.Equal(t, wantErr == "", result.IsValid, email)
+		if wantErr != "" {
+			assert.ErrorIs(t, result.LastError, mailcop.ErrNonASCII, email)
+			assert.EqualError(t, result.LastError, wantErr, email)
+			assert.Equal(t, mailcop.ReasonNonASCII, result.Reason, email)
+		}
+	}
+
+	assert.Equal(t, mailcop.ReasonNonASCII, v.ValidateDomain("münchen.de").Reason)
+	assert.True(t, v.ValidateDomain("xn--mnchen-3ya.de").IsValid)
+
+	t.Run("disabled", func(t *testing.T) {
+		opts.ASCIIOnly = false
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		assert.True(t, v.Validate("josé@münchen.de").IsValid)
+	})
+}
+
+func TestNormalizeUnicode(t *testing.T) {
+	composed := "jos\u00e9@m\u00fcnchen.de"     // é and ü as single code points
+	decomposed := "jose\u0301@mu\u0308nchen.de" // e and u followed by combining marks
+
+	opts := mailcop.DefaultOptions()
+	opts.NormalizeUnicode = true
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		email      string
+		wantDomain string
+	}{
+		{name: "composed", email: composed, wantDomain: "m\u00fcnchen.de"},
+		{name: "decomposed", email: decomposed, wantDomain: "m\u00fcnchen.de"},
+		{name: "named decomposed", email: "Jose\u0301 <" + decomposed + ">", wantDomain: "m\u00fcnchen.de"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			require.True(t, result.IsValid, result.LastError)
+			assert.Equal(t, composed, result.Address)
+			assert.Equal(t, tt.wantDomain, result.Domain)
+		})
+	}
+
+	t.Run("duplicates", func(t *testing.T) {
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+		v.RegisterExistingAddresses([]string{composed})
+
+		result := v.Validate(decomposed)
+		assert.True(t, result.IsDuplicate)
+		assert.Equal(t, composed, result.DuplicateOf)
+	})
+
+	t.Run("parsed addresses are left alone", func(t *testing.T) {
+		addr := &mail.Address{Address: decomposed}
+		assert.Equal(t, composed, v.ValidateAddress(addr).Address)
+		assert.Equal(t, decomposed, addr.Address)
+	})
+
+	t.Run("domains", func(t *testing.T) {
+		assert.Equal(t, "m\u00fcnchen.de", v.ValidateDomain("mu\u0308nchen.de").Domain)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.DefaultOptions())
+		require.NoError(t, err)
+		assert.Equal(t, decomposed, v.Validate(decomposed).Address)
+	})
+}
+
+func TestStrictRFC5321(t *testing.T) {
+	// label returns a domain label of n characters
+	label := func(n int) string { return strings.Repeat("a", n) }
+	longDomain := strings.Join([]string{label(63), label(63), label(63), label(60)}, ".") + ".com" // 256 characters
+
+	tests := []struct {
+		name    string
+		email   string
+		wantErr error
+	}{
+		{name: "within limits", email: label(64) + "@example.com"},
+		{name: "local part too long", email: label(65) + "@example.com", wantErr: mailcop.ErrLocalPartTooLong},
+		{name: "long local part with short domain", email: label(200) + "@b.com", wantErr: mailcop.ErrLocalPartTooLong},
+		{name: "domain too long", email: "user@" + longDomain, wantErr: mailcop.ErrDomainTooLong},
+		{name: "address too long", email: label(64) + "@" + label(63) + "." + label(63) + "." + label(58) + ".com", wantErr: mailcop.ErrAddressTooLong},
+	}
+
+	opts := mailcop.DefaultOptions()
+	opts.MaxEmailLength = 320 // Let the strict checks see addresses past the default limit
+	opts.StrictRFC5321 = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	lenient, err := mailcop.New(mailcop.Options{MaxEmailLength: 320})
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			if tt.wantErr != nil {
+				assert.False(t, result.IsValid)
+				assert.ErrorIs(t, result.LastError, tt.wantErr)
+			} else {
+				assert.True(t, result.IsValid)
+				assert.NoError(t, result.LastError)
+			}
+
+			assert.True(t, lenient.Validate(tt.email).IsValid, "limits are only enforced in strict mode")
+		})
+	}
+}
+
+func TestValidateDomain(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.CheckFreeProvider = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		domain      string
+		wantValid   bool
+		wantDomain  string
+		checkResult func(t *testing.T, result mailcop.ValidationResult)
+	}{
+		{domain: "company.org", wantValid: true, wantDomain: "company.org"},
+		{domain: " Company.ORG ", wantValid: true, wantDomain: "company.org"},
+		{
+			domain: "gmail.com", wantValid: true, wantDomain: "gmail.com",
+			checkResult: func(t *testing.T, result mailcop.ValidationResult) { assert.True(t, result.IsFreeProvider) },
+		},
+		{
+			domain: "tempmail.com", wantValid: true, wantDomain: "tempmail.com",
+			checkResult: func(t *testing.T, result mailcop.ValidationResult) { assert.True(t, result.IsDisposable) },
+		},
+		{
+			domain: "example.com", wantValid: true, wantDomain: "example.com",
+			checkResult: func(t *testing.T, result mailcop.ValidationResult) { assert.True(t, result.IsReserved) },
+		},
+		{
+			domain: "[192.168.1.1]", wantValid: true, wantDomain: "[192.168.1.1]",
+			checkResult: func(t *testing.T, result mailcop.ValidationResult) { assert.True(t, result.IsIPDomain) },
+		},
+		{domain: "", wantValid: false},
+		{domain: "user@company.org", wantValid: false},
+		{domain: "bad domain.com", wantValid: false, wantDomain: "bad domain.com"},
+		{domain: "company..org", wantValid: false, wantDomain: "company..org"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			result := v.ValidateDomain(tt.domain)
+			assert.Equal(t, tt.wantValid, result.IsValid)
+			assert.Equal(t, tt.wantDomain, result.Domain)
+			assert.Equal(t, tt.domain, result.Original)
+			assert.Empty(t, result.Address)
+			assert.Empty(t, result.Name)
+			if tt.wantValid {
+				assert.NoError(t, result.LastError)
+				assert.Positive(t, result.Score)
+			} else {
+				assert.Error(t, result.LastError)
+				assert.Zero(t, result.Score)
+			}
+			if tt.checkResult != nil {
+				tt.checkResult(t, result)
+			}
+		})
+	}
+
+	t.Run("matches Validate", func(t *testing.T) {
+		for _, domain := range []string{"company.org", "gmail.com", "tempmail.com", "example.com"} {
+			email := v.Validate("user@" + domain)
+			result := v.ValidateDomain(domain)
+			assert.Equal(t, email.IsValid, result.IsValid, domain)
+			assert.Equal(t, email.IsDisposable, result.IsDisposable, domain)
+			assert.Equal(t, email.IsFreeProvider, result.IsFreeProvider, domain)
+			assert.Equal(t, email.IsReserved, result.IsReserved, domain)
+			assert.Equal(t, email.Score, result.Score, domain)
+		}
+	})
+}
+
+func TestDomainSyntax(t *testing.T) {
+	v, err := mailcop.New(mailcop.DefaultOptions())
+	require.NoError(t, err)
+
+	tests := []struct {
+		domain    string
+		wantValid bool
+		wantErrIs bool // Whether the domain gets past the parser, so ErrInvalidDomainSyntax reports it
+	}{
+		{domain: "example.com", wantValid: true},
+		{domain: "a-b.example.com", wantValid: true},
+		{domain: "münchen.de", wantValid: true},
+		{domain: "xn--mnchen-3ya.de", wantValid: true},
+		{domain: "[192.168.1.1]", wantValid: true},
+		{domain: "gmail..com"},
+		{domain: ".example.com"},
+		{domain: "ex_ample.com", wantErrIs: true},
+		{domain: "exa!mple.com", wantErrIs: true},
+		{domain: "-example.com", wantErrIs: true},
+		{domain: "example-.com", wantErrIs: true},
+		{domain: strings.Repeat("a", 64) + ".com", wantErrIs: true},
+		{domain: strings.Repeat("a", 63) + ".com", wantValid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			for _, result := range []mailcop.ValidationResult{v.Validate("user@" + tt.domain), v.ValidateDomain(tt.domain)} {
+				assert.Equal(t, tt.wantValid, result.IsValid)
+				if !tt.wantValid {
+					assert.Equal(t, mailcop.ReasonSyntax, result.Reason)
+				}
+				if tt.wantErrIs {
+					assert.ErrorIs(t, result.LastError, mailcop.ErrInvalidDomainSyntax)
+				}
+			}
+		})
+	}
+
+	t.Run("check skipped", func(t *testing.T) {
+		v, err := mailcop.NewWithOptions(mailcop.WithDomainSyntaxCheck(false))
+		require.NoError(t, err)
+
+		for _, domain := range []string{"ex_ample.com", "-example.com"} {
+			assert.True(t, v.Validate("user@"+domain).IsValid, domain)
+			assert.True(t, v.ValidateDomain(domain).IsValid, domain)
+		}
+	})
+}
+
+func TestMaxDomainLabels(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.MaxDomainLabels = 4
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		domain    string
+		wantValid bool
+	}{
+		{domain: "example.com", wantValid: true},
+		{domain: "mail.eu.example.com", wantValid: true},
+		{domain: "a.mail.eu.example.com"},
+		{domain: "a.b.c.d.e.f.g.example.com"},
+		{domain: "[192.168.1.1]", wantValid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			for _, result := range []mailcop.ValidationResult{v.Validate("user@" + tt.domain), v.ValidateDomain(tt.domain)} {
+				assert.Equal(t, tt.wantValid, result.IsValid, result.LastError)
+				if !tt.wantValid {
+					assert.Equal(t, mailcop.ReasonTooManyLabels, result.Reason)
+					assert.ErrorIs(t, result.LastError, mailcop.ErrTooManyLabels)
+				}
+			}
+		})
+	}
+
+	t.Run("explained", func(t *testing.T) {
+		assert.Contains(t, v.Explain("user@a.b.c.d.example.com"), "Domain: failed: domain has too many labels: a.b.c.d.example.com has 6, at most 4 allowed")
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.DefaultOptions())
+		require.NoError(t, err)
+		assert.True(t, v.Validate("user@a.b.c.d.e.f.g.example.com").IsValid)
+	})
+}
+
+func TestCustomRules(t *testing.T) {
+	errRoleAccount := errors.New("role accounts are not allowed")
+	errNoFreeSales := errors.New("sales must use a company address")
+
+	var calls []string
+	opts := mailcop.DefaultOptions()
+	opts.CheckFreeProvider = true
+	opts.RequireTLD = true
+	opts.CustomRules = []func(mailcop.ValidationResult) error{
+		func(r mailcop.ValidationResult) error {
+			calls = append(calls, r.Address)
+			if strings.HasPrefix(r.Address, "info@") {
+				return errRoleAccount
+			}
+			return nil
+		},
+		func(r mailcop.ValidationResult) error {
+			if strings.HasPrefix(r.Address, "sales@") && r.IsFreeProvider {
+				return errNoFreeSales
+			}
+			return nil
+		},
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		email     string
+		wantValid bool
+		wantErr   error
+		wantCall  bool
+	}{
+		{email: "user@company.org", wantValid: true, wantCall: true},
+		{email: "info@company.org", wantValid: false, wantErr: errRoleAccount, wantCall: true},
+		{email: "sales@gmail.com", wantValid: false, wantErr: errNoFreeSales, wantCall: true},
+		{email: "sales@company.org", wantValid: true, wantCall: true},
+		{email: "info@company", wantValid: false, wantCall: false}, // Built-in checks fail first
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			calls = nil
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantValid, result.IsValid)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, result.LastError, tt.wantErr)
+			} else if tt.wantValid {
+				assert.NoError(t, result.LastError)
+			} else {
+				assert.Error(t, result.LastError)
+				assert.NotErrorIs(t, result.LastError, errRoleAccount)
+			}
+			assert.Equal(t, tt.wantCall, len(calls) == 1)
+		})
+	}
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+	opts.Resolver = staticResolver{"company.org": {"mx.company.org"}}
+	opts.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	v.Validate("user@company.org")
+	v.Validate("user@company.org")
+	require.Error(t, v.LoadTrustedDomains("file:///nonexistent.json"))
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, "DEBUG", entry["level"])
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 5)
+
+	assert.Equal(t, "list fetched", entries[0]["msg"])
+	assert.Equal(t, opts.DisposableDomainsURL, entries[0]["url"])
+	assert.Positive(t, entries[0]["count"])
+	assert.Contains(t, entries[0], "duration")
+
+	assert.Equal(t, "list refreshed", entries[1]["msg"])
+	assert.Equal(t, "disposable", entries[1]["list"])
+
+	assert.Equal(t, "DNS lookup", entries[2]["msg"])
+	assert.Equal(t, "company.org", entries[2]["domain"])
+	assert.Equal(t, "miss", entries[2]["cache"])
+	assert.Contains(t, entries[2], "latency")
+	assert.NotContains(t, entries[2], "error")
+
+	assert.Equal(t, "DNS lookup", entries[3]["msg"])
+	assert.Equal(t, "hit", entries[3]["cache"])
+
+	assert.Equal(t, "list fetch failed", entries[4]["msg"])
+	assert.Contains(t, entries[4], "error")
+
+	t.Run("nil logger", func(t *testing.T) {
+		opts.Logger = nil
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+		assert.True(t, v.Validate("user@company.org").IsValid)
+	})
+}
+
+func TestReason(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckFreeProvider = true
+	opts.CheckTLD = true
+	opts.RejectFreeProvider = true
+	opts.RejectNamedEmails = true
+	opts.RejectReserved = true
+	opts.RejectUnknownTLD = true
+	opts.RequireTLD = true
+	opts.StrictRFC5321 = true
+	opts.RejectUTF8LocalPart = true
+	opts.BannedLocalParts = []string{"test"}
+	opts.CustomRules = []func(mailcop.ValidationResult) error{
+		func(r mailcop.ValidationResult) error {
+			if strings.HasPrefix(r.Address, "blocked@") {
+				return errors.New("blocked")
+			}
+			return nil
+		},
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		email      string
+		wantReason mailcop.Reason
+	}{
+		{email: "user@company.org"},
+		{email: "not an email", wantReason: mailcop.ReasonSyntax},
+		{email: strings.Repeat("a", 65) + "@company.org", wantReason: mailcop.ReasonTooLong},
+		{email: "John <user@company.org>", wantReason: mailcop.ReasonNamed},
+		{email: "josé@company.org", wantReason: mailcop.ReasonUTF8LocalPart},
+		{email: "test@company.org", wantReason: mailcop.ReasonBannedLocalPart},
+		{email: "user@company", wantReason: mailcop.ReasonInvalidTLD},
+		{email: "user@company.notatld", wantReason: mailcop.ReasonUnknownTLD},
+		{email: "user@example.com", wantReason: mailcop.ReasonReserved},
+		{email: "user@gmail.com", wantReason: mailcop.ReasonFreeProvider},
+		{email: "blocked@company.org", wantReason: mailcop.ReasonCustomRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantReason, result.Reason)
+			assert.Equal(t, tt.wantReason == "", result.IsValid)
+		})
+	}
+}
+
+func TestBannedLocalParts(t *testing.T) {
+	dir := t.TempDir()
+	listPath := filepath.Join(dir, "banned.json")
+	require.NoError(t, os.WriteFile(listPath, []byte(`["fake", "Nobody"]`), 0o644))
+
+	opts := mailcop.DefaultOptions()
+	opts.BannedLocalParts = []string{"Test", " asdf "}
+	opts.BannedLocalPartsURL = "file://" + listPath
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	v.RegisterBannedLocalParts([]string{"qwerty", ""})
+
+	tests := []struct {
+		email      string
+		wantBanned bool
+	}{
+		{email: "test@company.org", wantBanned: true},
+		{email: "TEST@company.org", wantBanned: true},
+		{email: "test+signup@company.org", wantBanned: true},
+		{email: "asdf@company.org", wantBanned: true},
+		{email: "fake@company.org", wantBanned: true},
+		{email: "nobody@company.org", wantBanned: true},
+		{email: "qwerty@company.org", wantBanned: true},
+		{email: "tester@company.org"},
+		{email: "user+test@company.org"},
+		{email: "user@test.org"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.Equal(t, !tt.wantBanned, result.IsValid)
+			assert.Equal(t, tt.wantBanned, errors.Is(result.LastError, mailcop.ErrBannedLocalPart))
+		})
+	}
+
+	t.Run("missing list", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.BannedLocalPartsURL = "file://" + filepath.Join(dir, "missing.json")
+
+		_, err := mailcop.New(opts)
+		assert.Error(t, err)
+	})
+}
+
+func TestStripSubaddress(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.StripSubaddress = true
+	opts.BannedLocalParts = []string{"test"}
+	opts.SpamtrapPatterns = []string{"trap@example.org"}
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+	opts.CheckDisposable = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		email      string
+		address    string
+		subaddress string
+	}{
+		{"user+news@example.org", "user@example.org", "news"},
+		{"User+a+b@Example.ORG", "User@example.org", "a+b"},
+		{"user@example.org", "user@example.org", ""},
+		{"+news@example.org", "+news@example.org", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.True(t, result.IsValid)
+			assert.Equal(t, tt.address, result.Address)
+			assert.Equal(t, tt.subaddress, result.Subaddress)
+		})
+	}
+
+	t.Run("checks use the base address", func(t *testing.T) {
+		assert.True(t, v.Validate("trap+x@example.org").IsSpamtrap)
+		assert.Equal(t, mailcop.ReasonBannedLocalPart, v.Validate("test+1@example.org").Reason)
+
+		result := v.Validate("user+tag@tempmail.com")
+		assert.True(t, result.IsDisposable)
+		assert.Equal(t, "tempmail.com", result.Domain)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.DefaultOptions())
+		require.NoError(t, err)
+
+		result := v.Validate("user+news@example.org")
+		assert.Equal(t, "user+news@example.org", result.Address)
+		assert.Empty(t, result.Subaddress)
+	})
+}
+
+func TestMatchSubdomains(t *testing.T) {
+	disposableURL := "file://" + filepath.Join("testdata", "domains.json")
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = disposableURL
+
+	exact, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	opts.MatchSubdomains = true
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	v.RegisterDisposableDomains([]string{"burner.io"})
+
+	tests := []struct {
+		email          string
+		wantDisposable bool
+		wantSource     string
+		wantExact      bool // Whether it's disposable without MatchSubdomains
+	}{
+		{email: "user@tempmail.com", wantDisposable: true, wantSource: disposableURL, wantExact: true},
+		{email: "user@inbox.tempmail.com", wantDisposable: true, wantSource: disposableURL},
+		{email: "user@a.b.TEMPMAIL.com", wantDisposable: true, wantSource: disposableURL},
+		{email: "user@mx.burner.io", wantDisposable: true, wantSource: "registered"},
+		{email: "user@nottempmail.com"},
+		{email: "user@company.org"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantDisposable, result.IsDisposable)
+			assert.Equal(t, tt.wantSource, result.DisposableSource)
+
+			assert.Equal(t, tt.wantExact, exact.Validate(tt.email).IsDisposable)
+		})
+	}
+
+	t.Run("trusted subdomain", func(t *testing.T) {
+		v.RegisterTrustedDomains([]string{"corp.tempmail.com"})
+		assert.False(t, v.Validate("user@corp.tempmail.com").IsDisposable)
+	})
+}
+
+func TestPackageValidate(t *testing.T) {
+	assert.True(t, mailcop.IsValid(" user@company.org "))
+	assert.False(t, mailcop.IsValid("not an email"))
+
+	result := mailcop.ValidateDefault("John <user@company.org>")
+	assert.True(t, result.IsValid)
+	assert.Equal(t, "user@company.org", result.Address)
+	assert.False(t, result.HasMX)
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.True(t, mailcop.IsValid("user@company.org"))
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestEmbeddedDisposableList(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.UseEmbeddedDisposableList = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	result := v.Validate("user@mailinator.com")
+	assert.True(t, result.IsDisposable)
+	assert.Equal(t, "embedded", result.DisposableSource)
+	assert.False(t, v.Validate("user@company.org").IsDisposable)
+	assert.Equal(t, mailcop.EmbeddedDisposableListDate, v.DisposableListLastUpdated())
+
+	t.Run("custom URL overrides the embedded list", func(t *testing.T) {
+		opts := opts
+		opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		assert.False(t, v.Validate("user@mailinator.com").IsDisposable)
+		assert.Equal(t, opts.DisposableDomainsURL, v.Validate("user@tempmail.com").DisposableSource)
+	})
+}
+
+func TestExistingAddresses(t *testing.T) {
+	v, err := mailcop.New(mailcop.DefaultOptions())
+	require.NoError(t, err)
+
+	v.RegisterExistingAddresses([]string{
+		"user@gmail.com",
+		"Jane.Doe@Company.org",
+		"first.last@googlemail.com",
+		"not an address",
+	})
+
+	tests := []struct {
+		email           string
+		wantDuplicateOf string
+	}{
+		{email: "user@gmail.com", wantDuplicateOf: "user@gmail.com"},
+		{email: "user+spam@gmail.com", wantDuplicateOf: "user@gmail.com"},
+		{email: "U.S.E.R@googlemail.com", wantDuplicateOf: "user@gmail.com"},
+		{email: "jane.doe+news@company.org", wantDuplicateOf: "Jane.Doe@Company.org"},
+		{email: "firstlast@gmail.com", wantDuplicateOf: "first.last@googlemail.com"},
+		{email: "janedoe@company.org"}, // Dots only matter outside Gmail
+		{email: "other@gmail.com"},
+		{email: "+user@gmail.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.True(t, result.IsValid, "duplicates are flagged, not rejected")
+			assert.Equal(t, tt.wantDuplicateOf != "", result.IsDuplicate)
+			assert.Equal(t, tt.wantDuplicateOf, result.DuplicateOf)
+		})
+	}
+}
+
+func TestCollectAllErrors(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+	opts.RejectDisposable = true
+	opts.RejectNamedEmails = true
+	opts.BannedLocalParts = []string{"test"}
+	opts.RequireTLD = true
+	opts.CollectAllErrors = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	t.Run("every failure is collected", func(t *testing.T) {
+		result := v.Validate("Tester <test@tempmail.com>")
+		assert.False(t, result.IsValid)
+		require.Len(t, result.Errors, 3)
+		assert.Equal(t, result.LastError, result.Errors[0])
+		assert.Equal(t, mailcop.ReasonNamed, result.Reason)
+		assert.ErrorIs(t, result.Errors[1], mailcop.ErrBannedLocalPart)
+		assert.Contains(t, result.Errors[2].Error(), "disposable domain")
+	})
+
+	t.Run("a missing TLD is reported once", func(t *testing.T) {
+		opts := opts
+		opts.CheckTLD = true
+		opts.RejectUnknownTLD = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("user@company")
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, mailcop.ReasonInvalidTLD, result.Reason)
+	})
+
+	t.Run("syntax errors stop validation", func(t *testing.T) {
+		result := v.Validate("not an email")
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, result.LastError, result.Errors[0])
+	})
+
+	t.Run("DNS is skipped after a failure", func(t *testing.T) {
+		resolver := blockingResolver{calls: &atomic.Int32{}, release: make(chan struct{})}
+		close(resolver.release)
+
+		opts := opts
+		opts.CheckDNS = true
+		opts.Resolver = resolver
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		assert.False(t, v.Validate("test@company.org").IsValid)
+		assert.Equal(t, int32(0), resolver.calls.Load())
+
+		result := v.Validate("user@company.org")
+		assert.True(t, result.IsValid)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, int32(1), resolver.calls.Load())
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		opts := opts
+		opts.CollectAllErrors = false
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("Tester <test@tempmail.com>")
+		assert.Equal(t, mailcop.ReasonNamed, result.Reason)
+		assert.Nil(t, result.Errors)
+	})
+}
+
+func TestValidateInto(t *testing.T) {
+	v, err := mailcop.New(mailcop.DefaultOptions())
+	require.NoError(t, err)
+
+	var result mailcop.ValidationResult
+	v.ValidateInto("not an email", &result)
+	assert.False(t, result.IsValid)
+	assert.Error(t, result.LastError)
+
+	// The result is reset, so nothing carries over from the previous address
+	v.ValidateInto("John <user@company.org>", &result)
+	assert.Equal(t, v.Validate("John <user@company.org>").Address, result.Address)
+	assert.True(t, result.IsValid)
+	assert.NoError(t, result.LastError)
+	assert.Equal(t, "John", result.Name)
+
+	t.Run("slices are reused", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.CheckDNS = true
+		opts.CollectAllErrors = true
+		opts.RejectReserved = true
+		opts.RequireTLD = true
+		opts.Resolver = staticResolver{
+			"company.org": {"mx1.company.org", "mx2.company.org"},
+		}
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		var result mailcop.ValidationResult
+		v.ValidateInto("user@company.org", &result)
+		require.Len(t, result.MXRecords, 2)
+		mx := &result.MXRecords[0]
+
+		v.ValidateInto("user@example", &result)
+		require.Len(t, result.Errors, 2)
+		assert.Empty(t, result.MXRecords)
+		errs := &result.Errors[0]
+
+		v.ValidateInto("user@company.org", &result)
+		assert.True(t, result.IsValid)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, []string{"mx1.company.org", "mx2.company.org"}, result.MXRecords)
+		assert.Same(t, mx, &result.MXRecords[0])
+
+		v.ValidateInto("user@example", &result)
+		assert.Equal(t, v.Validate("user@example").Errors, result.Errors)
+		assert.Same(t, errs, &result.Errors[0])
+	})
+}
+
+func BenchmarkValidate(b *testing.B) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.Resolver = staticResolver{"company.org": {"mx1.company.org", "mx2.company.org"}}
+
+	v, err := mailcop.New(opts)
+	require.NoError(b, err)
+
+	b.Run("Validate", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = v.Validate("user@company.org")
+		}
+	})
+
+	b.Run("ValidateInto", func(b *testing.B) {
+		b.ReportAllocs()
+		var result mailcop.ValidationResult
+		for i := 0; i < b.N; i++ {
+			v.ValidateInto("user@company.org", &result)
+		}
+	})
+}
+
+func TestValidationResultString(t *testing.T) {
+	result := mailcop.ValidationResult{
+		Address:        "user@example.com",
+		IsValid:        true,
+		DNSStatus:      mailcop.DNSStatusOK,
+		DNSCacheHit:    true,
+		ValidationTime: 1200 * time.Microsecond,
+	}
+	assert.Equal(t, "user@example.com valid=true disposable=false free=false dns=ok cache=hit (1.2ms)", result.String())
+
+	v, err := mailcop.New(mailcop.DefaultOptions())
+	require.NoError(t, err)
+
+	s := v.Validate("invalid@").String()
+	assert.True(t, strings.HasPrefix(s, "invalid@ valid=false disposable=false free=false dns=not_checked reason=syntax ("), s)
+	assert.Contains(t, s, "): ")
+}
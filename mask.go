@@ -0,0 +1,196 @@
+package mailcop
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// MaskEmail returns a redacted form of an email address that's safe to log, like
+// "j**n@ex*****.com" for "john@example.com". The first and last characters of the
+// local part are kept, as are the first two characters of each domain label and the
+// public suffix. Every other character becomes "*", so lengths are preserved. Input
+// without an "@" is masked like a local part.
+func MaskEmail(email string) string {
+	return maskAddress(strings.TrimSpace(email), true)
+}
+
+// maskAddress masks the local part of an address, and its domain if maskDomain is set
+func maskAddress(address string, maskDomain bool) string {
+	at := strings.LastIndexByte(address, '@')
+	if at < 0 {
+		return maskLocalPart(address)
+	}
+
+	domain := address[at+1:]
+	if maskDomain {
+		domain = maskDomainName(domain)
+	}
+	return maskLocalPart(address[:at]) + "@" + domain
+}
+
+// maskLocalPart keeps the first and last characters of a local part, or only the
+// first if it's two characters long, and masks the rest
+func maskLocalPart(local string) string {
+	n := utf8.RuneCountInString(local)
+	if n < 3 {
+		return keepPrefix(local, n-1)
+	}
+
+	last, size := utf8.DecodeLastRuneInString(local)
+	return keepPrefix(local[:len(local)-size], 1) + string(last)
+}
+
+// maskDomainName masks each label of a domain before its public suffix, keeping the
+// first two characters of each (or one for two-character labels). Domain literals
+// like "[192.0.2.1]" are masked like a local part.
+func maskDomainName(domain string) string {
+	if domain == "" || strings.HasPrefix(domain, "[") {
+		return maskLocalPart(domain)
+	}
+
+	name, suffix := domain, ""
+	if ps, _ := publicsuffix.PublicSuffix(strings.ToLower(domain)); len(ps) < len(domain) {
+		name, suffix = domain[:len(domain)-len(ps)-1], domain[len(domain)-len(ps)-1:]
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		labels[i] = keepPrefix(label, min(2, utf8.RuneCountInString(label)-1))
+	}
+	return strings.Join(labels, ".") + suffix
+}
+
+// keepPrefix keeps the first n characters of s and replaces the rest with "*"
+func keepPrefix(s string, n int) string {
+	n = max(n, 0)
+	var b strings.Builder
+	for i, r := range s {
+		if n > 0 {
+			b.WriteRune(r)
+			n--
+			continue
+		}
+		b.WriteString(strings.Repeat("*", utf8.RuneCountInString(s[i:])))
+		break
+	}
+	return b.String()
+}
+
+// maskedLocalPart returns a local part to include in an error, masked when MaskInResults
+// is set so errors in masked results are safe to log
+func (v *Validator) maskedLocalPart(local string) string {
+	if !v.options.MaskInResults {
+		return local
+	}
+	return maskLocalPart(local)
+}
+
+// maskedParseError returns the message of a net/mail parse error. Some quote the rest
+// of the input, as in `mail: expected single address, got "..."`, which is masked when
+// MaskInResults is set.
+func (v *Validator) maskedParseError(err error) string {
+	msg := err.Error()
+	i := strings.IndexByte(msg, '"')
+	if !v.options.MaskInResults || i < 0 {
+		return msg
+	}
+	quoted, unquoteErr := strconv.Unquote(msg[i:])
+	if unquoteErr != nil {
+		return msg[:i] + `"..."`
+	}
+	return msg[:i] + strconv.Quote(maskLocalPart(quoted))
+}
+
+// maskedError is an error whose message has its domains masked. It unwraps to the
+// original error, so errors.Is still matches.
+type maskedError struct {
+	msg string
+	err error
+}
+
+func (e *maskedError) Error() string {
+	return e.msg
+}
+
+func (e *maskedError) Unwrap() error {
+	return e.err
+}
+
+// maskErrorDomains masks the domains in the errors and warnings of a result, which
+// messages like "disposable domain: tempmail.com" include
+func maskErrorDomains(result *ValidationResult) {
+	var pairs []string
+	for _, domain := range []string{result.Domain, result.RegistrableDomain, result.DisplayNameSpoofDomain} {
+		if domain != "" {
+			pairs = append(pairs, domain, maskDomainName(domain))
+		}
+	}
+	if len(pairs) == 0 {
+		return
+	}
+	// Replacements are tried in order, so the domain is masked as a whole rather than
+	// as the registrable domain it ends with
+	replacer := strings.NewReplacer(pairs...)
+
+	mask := func(err error) error {
+		if err == nil {
+			return nil
+		}
+		if msg := replacer.Replace(err.Error()); msg != err.Error() {
+			return &maskedError{msg: msg, err: err}
+		}
+		return err
+	}
+	lastError := result.LastError
+	result.LastError = mask(lastError)
+	for i, err := range result.Errors {
+		if err == lastError {
+			result.Errors[i] = result.LastError
+		} else {
+			result.Errors[i] = mask(err)
+		}
+	}
+	for i, warning := range result.Warnings {
+		result.Warnings[i] = replacer.Replace(warning)
+	}
+}
+
+// maskResult masks the addresses in a result when MaskInResults is set. Original is
+// replaced by the masked Address, or masked on its own if there's no Address. The
+// domain is only masked with MaskDomainInResults, in errors and warnings too. The
+// GravatarHash is cleared, since it identifies the address.
+func (v *Validator) maskResult(result *ValidationResult) {
+	if !v.options.MaskInResults {
+		return
+	}
+	maskDomain := v.options.MaskDomainInResults
+	result.GravatarHash = ""
+
+	switch {
+	case result.Address != "":
+		result.Address = maskAddress(result.Address, maskDomain)
+		result.Original = result.Address
+	case result.Domain != "" && normalizeDomain(result.Original) == result.Domain:
+		// From ValidateDomain, where the input is the domain
+		if maskDomain {
+			result.Original = maskDomainName(result.Domain)
+		}
+	default:
+		result.Original = maskAddress(strings.TrimSpace(result.Original), maskDomain)
+	}
+
+	if result.DuplicateOf != "" {
+		result.DuplicateOf = maskAddress(result.DuplicateOf, maskDomain)
+	}
+	result.Name = maskLocalPart(result.Name)
+
+	if maskDomain {
+		maskErrorDomains(result)
+		result.Domain = maskDomainName(result.Domain)
+		result.RegistrableDomain = maskDomainName(result.RegistrableDomain)
+		result.DisplayNameSpoofDomain = maskDomainName(result.DisplayNameSpoofDomain)
+	}
+}
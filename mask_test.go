@@ -0,0 +1,181 @@
+package mailcop_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestMaskEmail(t *testing.T) {
+	tests := map[string]string{
+		"john@example.com":            "j**n@ex*****.com",
+		"jo@example.com":              "j*@ex*****.com",
+		"j@example.com":               "*@ex*****.com",
+		"jane.doe@mail.example.co.uk": "j******e@ma**.ex*****.co.uk",
+		"josé@exämple.de":             "j**é@ex*****.de",
+		" user@a.io ":                 "u**r@*.io",
+		"user@[192.0.2.1]":            "u**r@[*********]",
+		"not an email":                "n**********l",
+		"":                            "",
+	}
+
+	for email, want := range tests {
+		assert.Equal(t, want, mailcop.MaskEmail(email), email)
+	}
+}
+
+func TestMaskInResults(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.MaskInResults = true
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	v.RegisterExistingAddresses([]string{"jane@example.com"})
+
+	result := v.Validate(`"Jane Doe" <Jane+promo@Example.com>`)
+	assert.True(t, result.IsValid)
+	assert.Equal(t, "J********o@example.com", result.Address)
+	assert.Equal(t, result.Address, result.Original)
+	assert.Equal(t, "J******e", result.Name)
+	assert.Equal(t, "j**e@example.com", result.DuplicateOf)
+	assert.Equal(t, "example.com", result.Domain)
+
+	result = v.Validate("not an email")
+	assert.False(t, result.IsValid)
+	assert.Equal(t, "n**********l", result.Original)
+
+	t.Run("domains", func(t *testing.T) {
+		opts := opts
+		opts.MaskDomainInResults = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("jane@mail.example.co.uk")
+		assert.Equal(t, "j**e@ma**.ex*****.co.uk", result.Address)
+		assert.Equal(t, "ma**.ex*****.co.uk", result.Domain)
+		assert.Equal(t, "ex*****.co.uk", result.RegistrableDomain)
+
+		result = v.ValidateDomain("example.com")
+		assert.Equal(t, "ex*****.com", result.Original)
+	})
+
+	t.Run("domain results keep the domain", func(t *testing.T) {
+		result := v.ValidateDomain("Example.com")
+		assert.Equal(t, "Example.com", result.Original)
+	})
+
+	t.Run("batches", func(t *testing.T) {
+		opts := opts
+		opts.DedupInput = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		for _, result := range v.ValidateMany([]string{"jane@example.com", " jane@EXAMPLE.com"}) {
+			assert.Equal(t, "j**e@example.com", result.Original)
+		}
+	})
+}
+
+func TestMaskInResultsErrors(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.MaskInResults = true
+	opts.CollectAllErrors = true
+	opts.GravatarHash = true
+	opts.BannedLocalParts = []string{"postmaster"}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	assertMasked := func(t *testing.T, result mailcop.ValidationResult, secret string) {
+		t.Helper()
+		require.Error(t, result.LastError)
+		assert.NotContains(t, result.LastError.Error(), secret)
+		for _, err := range result.Errors {
+			assert.NotContains(t, err.Error(), secret)
+		}
+		assert.Empty(t, result.GravatarHash)
+	}
+
+	t.Run("banned local part", func(t *testing.T) {
+		result := v.Validate("postmaster@example.com")
+		assertMasked(t, result, "postmaster")
+		assert.ErrorIs(t, result.LastError, mailcop.ErrBannedLocalPart)
+		assert.EqualError(t, result.LastError, "local part is not allowed: p********r")
+	})
+
+	t.Run("non-ASCII", func(t *testing.T) {
+		opts := opts
+		opts.ASCIIOnly = true
+		opts.RejectUTF8LocalPart = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("josé.garcía@example.com")
+		assertMasked(t, result, "josé.garcía")
+		assert.ErrorIs(t, result.LastError, mailcop.ErrNonASCII)
+		assert.EqualError(t, result.LastError, "address contains non-ASCII characters in local part: j*********a")
+		require.Len(t, result.Errors, 2)
+		assert.EqualError(t, result.Errors[1], "non-ASCII local part requires SMTPUTF8: j*********a")
+	})
+
+	t.Run("parse errors", func(t *testing.T) {
+		result := v.Validate("jane.doe@example.com, john.smith@example.com")
+		assertMasked(t, result, "john.smith")
+		assert.ErrorContains(t, result.LastError, "expected single address")
+	})
+
+	t.Run("domains", func(t *testing.T) {
+		opts := opts
+		opts.MaskDomainInResults = true
+		opts.RejectReserved = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("jane@example.com")
+		assertMasked(t, result, "example")
+		assert.Equal(t, mailcop.ReasonReserved, result.Reason)
+		assert.Equal(t, "reserved domain: ex*****.com", result.LastError.Error())
+
+		opts.Severities = map[mailcop.CheckType]mailcop.Severity{mailcop.CheckTypeReserved: mailcop.SeverityWarn}
+		v, err = mailcop.New(opts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"reserved domain: ex*****.com"}, v.Validate("jane@example.com").Warnings)
+	})
+
+	t.Run("domain syntax", func(t *testing.T) {
+		opts := opts
+		opts.MaskDomainInResults = true
+		opts.ASCIIOnly = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.ValidateDomain("-secret.com")
+		assertMasked(t, result, "secret")
+		assert.ErrorIs(t, result.LastError, mailcop.ErrInvalidDomainSyntax)
+		assert.NotContains(t, result.Original, "secret")
+
+		result = v.ValidateDomain("secrét.com")
+		assertMasked(t, result, "secrét")
+		assert.ErrorIs(t, result.LastError, mailcop.ErrNonASCII)
+	})
+
+	t.Run("unmasked", func(t *testing.T) {
+		opts := opts
+		opts.MaskInResults = false
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("postmaster@example.com")
+		assert.EqualError(t, result.LastError, "local part is not allowed: postmaster")
+		assert.NotEmpty(t, result.GravatarHash)
+	})
+}
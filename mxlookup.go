@@ -1,94 +1,260 @@
 package mailcop
 
 import (
+	"context"
 	"fmt"
-	"net"
+	"net/mail"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 )
 
-// dnsResult holds the result of a DNS lookup and the time it was cached. Used in the DNS cache.
-type dnsResult struct {
-	err      error
-	cachedAt time.Time
-	lastUsed time.Time // Track when this entry was last accessed
-}
-
 // validateMX performs a DNS lookup for the MX records of a domain. It caches the result for future lookups.
+// A domain that publishes a null MX record returns ErrNullMX.
 func (v *Validator) validateMX(domain string) error {
-	if !v.options.CheckDNS {
-		return nil
+	result, _ := v.lookupMX(context.Background(), domain)
+	if result.Err == nil && isNullMX(result.MX) {
+		return fmt.Errorf("%w: %s", ErrNullMX, domain)
+	}
+	return result.Err
+}
+
+// lookupMX returns the (possibly cached) MX lookup result for a domain and whether it
+// was served from the DNS cache. DNSSEC validation is requested when the resolver supports it.
+// Lookups are bounded by DNSTimeout and by ctx, and concurrent lookups of a domain are coalesced.
+func (v *Validator) lookupMX(ctx context.Context, domain string) (result DNSCacheEntry, cacheHit bool) {
+	if !v.checkDNS() {
+		return DNSCacheEntry{}, false
 	}
 
 	// Try cache first
-	v.mu.RLock()
-	if result, ok := v.dnsCache[domain]; ok {
-		if time.Since(result.cachedAt) < v.options.DNSCacheTTL {
-			// Update last used time under write lock
-			v.mu.RUnlock()
-			v.mu.Lock()
-			if result, stillExists := v.dnsCache[domain]; stillExists {
-				result.lastUsed = time.Now()
-			}
-			v.mu.Unlock()
-			return result.err
+	start := time.Now()
+	key := v.dnsCacheKey(domain)
+	if cached, ok := v.cachedMX(key); ok {
+		v.stats.dnsCacheHits.Add(1)
+		v.logger.Debug("DNS lookup", "domain", domain, "cache", "hit", "latency", time.Since(start))
+		return cached, true
+	}
+	v.stats.dnsCacheMisses.Add(1)
+
+	// Concurrent lookups of the same domain share one query. The query isn't tied to
+	// any caller's ctx, so a canceled caller doesn't fail the others.
+	flight := v.mxFlight.DoChan(key, func() (any, error) {
+		result := v.resolveMX(domain)
+
+		attrs := []any{"domain", domain, "cache", "miss", "latency", time.Since(start)}
+		if result.Err != nil {
+			attrs = append(attrs, "error", result.Err)
 		}
+		v.logger.Debug("DNS lookup", attrs...)
+		v.cacheMX(key, result)
+		return result, nil
+	})
+
+	select {
+	case r := <-flight:
+		return r.Val.(DNSCacheEntry), false
+	case <-ctx.Done():
+		return DNSCacheEntry{Err: fmt.Errorf("DNS lookup canceled: %v", ctx.Err())}, false
 	}
-	v.mu.RUnlock()
+}
+
+// resolveMX queries the resolver for the MX records of a domain, giving up after DNSTimeout
+func (v *Validator) resolveMX(domain string) DNSCacheEntry {
+	ctx, cancel := context.WithTimeout(context.Background(), v.options.DNSTimeout)
+	defer cancel()
 
-	// Perform actual lookup with timeout
-	done := make(chan error, 1)
+	done := make(chan DNSCacheEntry, 1)
 	go func() {
-		_, err := net.LookupMX(domain)
-		done <- err
+		var r DNSCacheEntry
+		if secure, ok := v.resolver.(DNSSECResolver); ok {
+			r.MX, r.Authenticated, r.Err = secure.LookupMXSecure(ctx, domain)
+		} else {
+			r.MX, r.Err = v.resolver.LookupMX(ctx, domain)
+		}
+		done <- r
 	}()
 
-	var lookupErr error
 	select {
-	case err := <-done:
-		lookupErr = err
-	case <-time.After(v.options.DNSTimeout):
-		lookupErr = fmt.Errorf("DNS lookup timeout after %v", v.options.DNSTimeout)
-	}
-
-	// Cache the result
-	v.mu.Lock()
-	defer v.mu.Unlock()
-
-	now := time.Now()
-
-	// If we're at capacity, remove LRU entry
-	if len(v.dnsCache) >= v.options.DNSCacheSize {
-		var (
-			lruKey     string
-			lruTime    time.Time
-			firstEntry = true
-		)
-
-		// First remove any expired entries
-		for domain, entry := range v.dnsCache {
-			if now.Sub(entry.cachedAt) >= v.options.DNSCacheTTL {
-				delete(v.dnsCache, domain)
-				continue
-			}
-			// Track LRU among non-expired entries
-			if firstEntry || entry.lastUsed.Before(lruTime) {
-				lruKey = domain
-				lruTime = entry.lastUsed
-				firstEntry = false
-			}
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		return DNSCacheEntry{Err: fmt.Errorf("%w after %v", errDNSTimeout, v.options.DNSTimeout)}
+	}
+}
+
+// dnsCacheKey returns the DNS cache key for a name. Lookups through a per-call resolver
+// are keyed by its name as well, as in "ns1|example.com", so resolvers don't share results.
+func (v *Validator) dnsCacheKey(name string) string {
+	if v.resolverScope == "" {
+		return name
+	}
+	return v.resolverScope + "|" + name
+}
+
+// cachedMX returns a cached lookup result if one exists and has not expired.
+// A hit marks the entry as most recently used without renewing CachedAt.
+func (v *Validator) cachedMX(key string) (DNSCacheEntry, bool) {
+	result, ok := v.dnsCache.Get(key)
+	if !ok || time.Since(result.CachedAt) >= v.dnsTTL(result) {
+		return DNSCacheEntry{}, false
+	}
+	return result, true
+}
+
+// cacheMX stores a lookup result, replacing any existing (expired) entry for the key.
+// A failure following another failure extends the count of consecutive failures.
+func (v *Validator) cacheMX(key string, result DNSCacheEntry) {
+	if result.Err != nil {
+		result.Failures = 1
+		if previous, ok := v.dnsCache.Get(key); ok && previous.Err != nil {
+			result.Failures = previous.Failures + 1
 		}
+	}
+	result.CachedAt = time.Now()
+	v.dnsCache.Add(key, result)
+}
+
+// dnsTTL returns how long a cached result stays valid. Failed lookups use the
+// shorter negative TTL so newly-configured domains recover quickly, doubled for each
+// consecutive failure after the first up to DNSCacheTTL, so a batch with many addresses
+// at a dead domain doesn't wait out DNSTimeout again every time the entry expires.
+func (v *Validator) dnsTTL(result DNSCacheEntry) time.Duration {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if result.Err == nil {
+		return v.options.DNSCacheTTL
+	}
 
-		// If still at capacity, remove LRU entry
-		if len(v.dnsCache) >= v.options.DNSCacheSize {
-			delete(v.dnsCache, lruKey)
+	ttl, limit := v.options.DNSNegativeCacheTTL, max(v.options.DNSCacheTTL, v.options.DNSNegativeCacheTTL)
+	for i := 1; i < result.Failures && ttl < limit; i++ {
+		ttl *= 2
+	}
+	return min(ttl, limit)
+}
+
+// warmMXCache resolves each distinct domain in emails once, populating the DNS
+// cache so the per-email validations that follow don't race to look up the same
+// domain. Warming is skipped when there are more distinct domains than the cache can hold.
+func (v *Validator) warmMXCache(emails []string) {
+	if !v.checkDNS() {
+		return
+	}
+
+	var domains []string
+	for _, email := range emails {
+		addr, err := mail.ParseAddress(cleanInput(email))
+		if err != nil {
+			continue
 		}
+		domains = append(domains, addr.Address[strings.LastIndexByte(addr.Address, '@')+1:])
+	}
+
+	domains = uniqueDomains(domains)
+	if len(domains) > v.options.DNSCacheSize {
+		return
+	}
+
+	_ = v.resolveDomains(context.Background(), domains)
+}
+
+// PreloadDomains warms the DNS cache for a known set of domains, such as the ones most
+// users sign up with, so the first validations for them are served from the cache.
+// Lookups run concurrently, limited by MaxConcurrency when set. Failed lookups are
+// cached like any other result; an error is only returned if CheckDNS is disabled or
+// ctx is done before every domain was resolved.
+func (v *Validator) PreloadDomains(ctx context.Context, domains []string) error {
+	if !v.checkDNS() {
+		return fmt.Errorf("DNS checks are disabled")
+	}
+
+	return v.resolveDomains(ctx, uniqueDomains(domains))
+}
+
+// WouldCheckDNS reports whether Validate would look up the MX records of an email's
+// domain, to estimate how many network lookups a batch will incur. That's the case when
+// CheckDNS is enabled, the domain isn't an IP address, none of the checks that run
+// before the MX lookup reject the email and the lookup isn't in the DNS cache. The
+// checks run without any lookups and don't count towards Stats. Note that a domain
+// whose MX lookup is cached may still need SPF or DMARC lookups.
+func (v *Validator) WouldCheckDNS(email string) bool {
+	v.mu.RLock()
+	options, allowed := v.options, v.allowedDomains
+	v.mu.RUnlock()
+
+	mx := slices.Index(options.CheckOrder, CheckTypeMX)
+	if !options.CheckDNS || mx < 0 {
+		return false
 	}
 
-	v.dnsCache[domain] = dnsResult{
-		err:      lookupErr,
-		cachedAt: now,
-		lastUsed: now,
+	// IP domains can't have MX records and aren't counted, so their reverse DNS is skipped
+	options.CheckOrder = options.CheckOrder[:mx]
+	options.RequireIPReverseDNS = false
+	options.CustomRules = nil
+	view := &Validator{
+		options:        options,
+		allowedDomains: allowed,
+		resolver:       v.resolver,
+		resolverScope:  v.resolverScope,
+		verdicts:       v.verdicts,
+		validatorState: v.validatorState,
 	}
 
-	return lookupErr
+	result := view.validate(email)
+	if result.LastError != nil || result.IsIPDomain {
+		return false
+	}
+	_, cached := v.cachedMX(v.dnsCacheKey(result.Domain))
+	return !cached
+}
+
+// resolveDomains looks up the MX records of each domain concurrently, limited by
+// MaxConcurrency when set. It stops starting new lookups once ctx is done.
+func (v *Validator) resolveDomains(ctx context.Context, domains []string) error {
+	var sem chan struct{}
+	if v.options.MaxConcurrency > 0 {
+		sem = make(chan struct{}, v.options.MaxConcurrency)
+	}
+
+	var wg sync.WaitGroup
+	for _, domain := range domains {
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(d string) {
+			defer wg.Done()
+			if sem != nil {
+				defer func() { <-sem }()
+			}
+			_, _ = v.lookupMX(ctx, d)
+		}(domain)
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// uniqueDomains normalizes domains and removes blanks and duplicates, keeping the first occurrence
+func uniqueDomains(domains []string) []string {
+	seen := make(map[string]struct{}, len(domains))
+	unique := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = normalizeDomain(domain)
+		if _, ok := seen[domain]; ok || domain == "" {
+			continue
+		}
+		seen[domain] = struct{}{}
+		unique = append(unique, domain)
+	}
+	return unique
 }
@@ -1,6 +1,10 @@
 package mailcop
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -79,19 +83,15 @@ func TestDNSCache(t *testing.T) {
 				err := v.validateMX("gmail.com")
 				require.NoError(t, err)
 
-				v.mu.RLock()
-				initialResult, exists := v.dnsCache["gmail.com"]
-				v.mu.RUnlock()
+				initialResult, exists := v.dnsCache.Get("gmail.com")
 				require.True(t, exists)
 
 				err = v.validateMX("gmail.com")
 				require.NoError(t, err)
 
-				v.mu.RLock()
-				secondResult, exists := v.dnsCache["gmail.com"]
-				v.mu.RUnlock()
+				secondResult, exists := v.dnsCache.Get("gmail.com")
 				require.True(t, exists)
-				assert.Equal(t, initialResult.cachedAt, secondResult.cachedAt,
+				assert.Equal(t, initialResult.CachedAt, secondResult.CachedAt,
 					"cache entry should not be renewed on hit")
 			},
 		},
@@ -101,9 +101,7 @@ func TestDNSCache(t *testing.T) {
 				err := v.validateMX("microsoft.com")
 				require.NoError(t, err)
 
-				v.mu.RLock()
-				initialResult, exists := v.dnsCache["microsoft.com"]
-				v.mu.RUnlock()
+				initialResult, exists := v.dnsCache.Get("microsoft.com")
 				require.True(t, exists, "entry should be in cache")
 
 				time.Sleep(3 * time.Second)
@@ -111,11 +109,9 @@ func TestDNSCache(t *testing.T) {
 				err = v.validateMX("microsoft.com")
 				require.NoError(t, err)
 
-				v.mu.RLock()
-				newResult, exists := v.dnsCache["microsoft.com"]
-				v.mu.RUnlock()
+				newResult, exists := v.dnsCache.Get("microsoft.com")
 				require.True(t, exists, "entry should still be in cache")
-				assert.True(t, newResult.cachedAt.After(initialResult.cachedAt),
+				assert.True(t, newResult.CachedAt.After(initialResult.CachedAt),
 					"cache entry should have been renewed after expiration")
 			},
 		},
@@ -139,12 +135,10 @@ func TestDNSCache(t *testing.T) {
 				err = v.validateMX("yahoo.com")
 				require.NoError(t, err)
 
-				v.mu.RLock()
-				_, hasGmail := v.dnsCache["gmail.com"]
-				_, hasMicrosoft := v.dnsCache["microsoft.com"]
-				_, hasYahoo := v.dnsCache["yahoo.com"]
-				cacheSize := len(v.dnsCache)
-				v.mu.RUnlock()
+				_, hasGmail := v.dnsCache.Get("gmail.com")
+				_, hasMicrosoft := v.dnsCache.Get("microsoft.com")
+				_, hasYahoo := v.dnsCache.Get("yahoo.com")
+				cacheSize := v.dnsCache.Len()
 
 				assert.True(t, hasGmail, "gmail.com should still be in cache as MRU")
 				assert.False(t, hasMicrosoft, "microsoft.com should have been evicted as LRU")
@@ -160,3 +154,295 @@ func TestDNSCache(t *testing.T) {
 		})
 	}
 }
+
+func TestDNSCacheEviction(t *testing.T) {
+	opts := DefaultOptions()
+	opts.CheckDNS = true
+	opts.DNSCacheSize = 3
+	opts.DNSCacheTTL = time.Hour
+
+	v, err := New(opts)
+	require.NoError(t, err)
+
+	v.cacheMX("a.com", DNSCacheEntry{})
+	v.cacheMX("b.com", DNSCacheEntry{})
+	v.cacheMX("c.com", DNSCacheEntry{})
+
+	// Touch a.com so b.com becomes the least recently used entry
+	initial, ok := v.cachedMX("a.com")
+	require.True(t, ok)
+
+	v.cacheMX("d.com", DNSCacheEntry{})
+
+	_, hasA := v.cachedMX("a.com")
+	_, hasB := v.cachedMX("b.com")
+	assert.True(t, hasA, "a.com should survive as recently used")
+	assert.False(t, hasB, "b.com should have been evicted as LRU")
+	assert.Equal(t, 3, v.dnsCache.Len())
+
+	hit, ok := v.cachedMX("a.com")
+	require.True(t, ok)
+	assert.Equal(t, initial.CachedAt, hit.CachedAt, "cache hit should not renew CachedAt")
+
+	// Expired entries are treated as misses
+	v.options.DNSCacheTTL = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	_, ok = v.cachedMX("a.com")
+	assert.False(t, ok)
+}
+
+func TestDNSNegativeCacheTTL(t *testing.T) {
+	opts := DefaultOptions()
+	opts.CheckDNS = true
+	opts.DNSCacheTTL = time.Hour
+	opts.DNSNegativeCacheTTL = 50 * time.Millisecond
+
+	v, err := New(opts)
+	require.NoError(t, err)
+
+	v.cacheMX("good.com", DNSCacheEntry{})
+	v.cacheMX("bad.com", DNSCacheEntry{Err: fmt.Errorf("no such host")})
+
+	_, ok := v.cachedMX("bad.com")
+	require.True(t, ok, "negative result should be cached initially")
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, ok = v.cachedMX("bad.com")
+	assert.False(t, ok, "negative result should expire after DNSNegativeCacheTTL")
+
+	_, ok = v.cachedMX("good.com")
+	assert.True(t, ok, "positive result should still be cached")
+}
+
+func TestDNSFailureBackoff(t *testing.T) {
+	opts := DefaultOptions()
+	opts.CheckDNS = true
+	opts.DNSCacheTTL = time.Hour
+	opts.DNSNegativeCacheTTL = time.Minute
+
+	v, err := New(opts)
+	require.NoError(t, err)
+
+	t.Run("consecutive failures are counted", func(t *testing.T) {
+		failures := func() int {
+			entry, ok := v.dnsCache.Get("dead.com")
+			require.True(t, ok)
+			return entry.Failures
+		}
+
+		for want := 1; want <= 3; want++ {
+			v.cacheMX("dead.com", DNSCacheEntry{Err: errDNSTimeout})
+			assert.Equal(t, want, failures())
+		}
+
+		v.cacheMX("dead.com", DNSCacheEntry{})
+		assert.Zero(t, failures(), "a successful lookup resets the count")
+
+		v.cacheMX("dead.com", DNSCacheEntry{Err: errDNSTimeout})
+		assert.Equal(t, 1, failures())
+	})
+
+	t.Run("negative TTL doubles", func(t *testing.T) {
+		tests := map[int]time.Duration{
+			1:   time.Minute,
+			2:   2 * time.Minute,
+			3:   4 * time.Minute,
+			6:   32 * time.Minute,
+			7:   time.Hour, // Capped at DNSCacheTTL
+			100: time.Hour,
+		}
+		for failures, want := range tests {
+			entry := DNSCacheEntry{Err: errDNSTimeout, Failures: failures}
+			assert.Equal(t, want, v.dnsTTL(entry), "after %d failures", failures)
+		}
+		assert.Equal(t, time.Hour, v.dnsTTL(DNSCacheEntry{}))
+	})
+
+	t.Run("expired entries back off", func(t *testing.T) {
+		v.cacheMX("flaky.com", DNSCacheEntry{Err: errDNSTimeout})
+		v.cacheMX("flaky.com", DNSCacheEntry{Err: errDNSTimeout})
+
+		// Cached 90 seconds ago: past the base negative TTL, but not the doubled one
+		entry, _ := v.dnsCache.Get("flaky.com")
+		entry.CachedAt = time.Now().Add(-90 * time.Second)
+		v.dnsCache.Add("flaky.com", entry)
+
+		_, ok := v.cachedMX("flaky.com")
+		assert.True(t, ok)
+	})
+}
+
+func TestDNSNegativeCacheTTLDefault(t *testing.T) {
+	opts := DefaultOptions()
+	opts.DNSNegativeCacheTTL = 0
+	opts.DNSCacheTTL = 2 * time.Second
+
+	v, err := New(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, v.options.DNSNegativeCacheTTL,
+		"negative TTL should not exceed the positive TTL by default")
+}
+
+func TestDNSCacheHit(t *testing.T) {
+	opts := DefaultOptions()
+	opts.CheckDNS = true
+	opts.DNSCacheTTL = time.Hour
+
+	v, err := New(opts)
+	require.NoError(t, err)
+
+	// Seed the cache so no network lookup is needed
+	v.cacheMX("cached.com", DNSCacheEntry{})
+
+	result := v.Validate("user@cached.com")
+	assert.True(t, result.IsValid)
+	assert.True(t, result.DNSCacheHit)
+
+	v.options.CheckDNS = false
+	result = v.Validate("user@cached.com")
+	assert.False(t, result.DNSCacheHit, "no cache hit is reported when DNS checks are disabled")
+}
+
+func TestWarmMXCache(t *testing.T) {
+	opts := DefaultOptions()
+	opts.CheckDNS = true
+	opts.DNSCacheTTL = time.Hour
+	opts.DNSTimeout = 100 * time.Millisecond
+	opts.MaxConcurrency = 2
+
+	v, err := New(opts)
+	require.NoError(t, err)
+
+	// Seed the cache so warming doesn't need the network for this domain
+	v.cacheMX("warm.com", DNSCacheEntry{})
+
+	v.warmMXCache([]string{
+		"a@warm.com",
+		"b@WARM.com",
+		"John <c@warm.com>",
+		"invalid@",
+		"d@nonexistent.invalid",
+		"e@nonexistent.invalid",
+	})
+
+	_, hasWarm := v.dnsCache.Get("warm.com")
+	_, hasInvalid := v.dnsCache.Get("nonexistent.invalid")
+	size := v.dnsCache.Len()
+
+	assert.True(t, hasWarm)
+	assert.True(t, hasInvalid, "distinct domains should be resolved and cached")
+	assert.Equal(t, 2, size, "each distinct domain should be cached once")
+
+	results := v.ValidateMany([]string{"a@warm.com", "b@warm.com"})
+	for _, result := range results {
+		assert.True(t, result.IsValid)
+		assert.True(t, result.DNSCacheHit)
+	}
+}
+
+// mapCache is a minimal DNSCache without eviction
+type mapCache struct {
+	mu      sync.Mutex
+	entries map[string]DNSCacheEntry
+}
+
+func (c *mapCache) Get(domain string) (DNSCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[domain]
+	return entry, ok
+}
+
+func (c *mapCache) Add(domain string, entry DNSCacheEntry) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[domain] = entry
+	return false
+}
+
+func (c *mapCache) Remove(domain string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[domain]
+	delete(c.entries, domain)
+	return ok
+}
+
+func (c *mapCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func TestCustomDNSCache(t *testing.T) {
+	cache := &mapCache{entries: make(map[string]DNSCacheEntry)}
+
+	opts := DefaultOptions()
+	opts.CheckDNS = true
+	opts.DNSCache = cache
+	opts.DNSCacheTTL = time.Hour
+
+	v, err := New(opts)
+	require.NoError(t, err)
+
+	v.cacheMX("custom.com", DNSCacheEntry{MX: []*net.MX{{Host: "mx.custom.com.", Pref: 10}}})
+	assert.Equal(t, 1, cache.Len())
+
+	entry, ok := cache.Get("custom.com")
+	require.True(t, ok)
+	assert.False(t, entry.CachedAt.IsZero(), "CachedAt should be set by the validator")
+
+	result := v.Validate("user@custom.com")
+	assert.True(t, result.IsValid)
+	assert.True(t, result.DNSCacheHit)
+	assert.True(t, result.HasMX)
+
+	// Entries older than the TTL are ignored even if the cache still holds them
+	cache.Add("stale.com", DNSCacheEntry{CachedAt: time.Now().Add(-2 * time.Hour)})
+	_, ok = v.cachedMX("stale.com")
+	assert.False(t, ok)
+}
+
+func TestPreloadDomains(t *testing.T) {
+	opts := DefaultOptions()
+	opts.CheckDNS = true
+	opts.DNSCacheTTL = time.Hour
+	opts.DNSTimeout = 100 * time.Millisecond
+	opts.MaxConcurrency = 2
+
+	v, err := New(opts)
+	require.NoError(t, err)
+
+	// Seed the cache so preloading doesn't need the network for this domain
+	v.cacheMX("preloaded.com", DNSCacheEntry{})
+
+	err = v.PreloadDomains(context.Background(), []string{"preloaded.com", " PRELOADED.com", "nonexistent.invalid", ""})
+	require.NoError(t, err)
+
+	_, hasPreloaded := v.dnsCache.Get("preloaded.com")
+	_, hasInvalid := v.dnsCache.Get("nonexistent.invalid")
+	assert.True(t, hasPreloaded)
+	assert.True(t, hasInvalid, "failed lookups are cached too")
+	assert.Equal(t, 2, v.dnsCache.Len())
+
+	result := v.Validate("user@preloaded.com")
+	assert.True(t, result.DNSCacheHit)
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := v.PreloadDomains(ctx, []string{"canceled.invalid"})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, ok := v.dnsCache.Get("canceled.invalid")
+		assert.False(t, ok, "nothing is cached once the context is done")
+	})
+
+	t.Run("DNS disabled", func(t *testing.T) {
+		v, err := New(DefaultOptions())
+		require.NoError(t, err)
+		assert.Error(t, v.PreloadDomains(context.Background(), []string{"example.com"}))
+	})
+}
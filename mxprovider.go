@@ -0,0 +1,86 @@
+package mailcop
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// ErrMailProviderNotAllowed is returned in ValidationResult.LastError when
+// RequiredMXSuffixes is set and none of a domain's MX hosts match one of them
+var ErrMailProviderNotAllowed = errors.New("mail provider is not allowed")
+
+// DefaultMXProviderPatterns returns the default mail providers detected from MX hosts,
+// keyed by provider name. Each pattern is a host suffix like "google.com", which
+// matches "google.com" itself and any host under it, such as "aspmx.l.google.com".
+func DefaultMXProviderPatterns() map[string][]string {
+	return map[string][]string{
+		"apple":     {"icloud.com"},
+		"fastmail":  {"messagingengine.com"},
+		"google":    {"google.com", "googlemail.com"},
+		"microsoft": {"outlook.com", "hotmail.com"},
+		"proton":    {"protonmail.ch", "proton.me"},
+		"yahoo":     {"yahoodns.net"},
+		"yandex":    {"yandex.net", "yandex.ru"},
+		"zoho":      {"zoho.com", "zoho.eu", "zoho.in", "zohomail.com"},
+	}
+}
+
+// RegisterMXProviderPatterns adds mail providers to detect from MX hosts, keyed by
+// provider name, using the same host suffixes as DefaultMXProviderPatterns. A leading
+// "." or "*." is ignored. A suffix registered again moves to the new provider.
+func (v *Validator) RegisterMXProviderPatterns(patterns map[string][]string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.mxProviders == nil {
+		v.mxProviders = make(map[string]string)
+	}
+	for provider, suffixes := range patterns {
+		for _, suffix := range suffixes {
+			if suffix = mxSuffix(suffix); suffix != "" {
+				v.mxProviders[suffix] = provider
+			}
+		}
+	}
+}
+
+// detectMailProvider returns the provider of the first MX host, in the order given,
+// that matches a registered pattern, or "" if none does
+func (v *Validator) detectMailProvider(records []*net.MX) string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for _, record := range records {
+		// Try the host itself and then each parent domain
+		for host := normalizeDomain(record.Host); host != ""; {
+			if provider, ok := v.mxProviders[host]; ok {
+				return provider
+			}
+			_, host, _ = strings.Cut(host, ".")
+		}
+	}
+	return ""
+}
+
+// matchMXSuffix returns the first of suffixes, without its leading dot, that an MX host
+// ends with, trying hosts in the order given. A suffix like ".google.com" matches
+// "aspmx.l.google.com." and "google.com" itself.
+func matchMXSuffix(records []*net.MX, suffixes []string) (string, bool) {
+	for _, record := range records {
+		host := normalizeDomain(record.Host)
+		for _, suffix := range suffixes {
+			suffix = mxSuffix(suffix)
+			if suffix != "" && (host == suffix || strings.HasSuffix(host, "."+suffix)) {
+				return suffix, true
+			}
+		}
+	}
+	return "", false
+}
+
+// mxSuffix normalizes an MX host suffix, dropping a leading "*." or "."
+func mxSuffix(suffix string) string {
+	suffix = strings.TrimPrefix(normalizeDomain(suffix), "*.")
+	return strings.TrimPrefix(suffix, ".")
+}
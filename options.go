@@ -0,0 +1,399 @@
+package mailcop
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Option configures a Validator created with NewWithOptions
+type Option func(*Options)
+
+// NewWithOptions creates a validator from DefaultOptions with the given options
+// applied in order. It is a convenience over New for changing a few settings.
+func NewWithOptions(opts ...Option) (*Validator, error) {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return New(options)
+}
+
+// WithOptions replaces all options with the given struct. Options applied after
+// it override individual settings.
+func WithOptions(options Options) Option {
+	return func(o *Options) {
+		*o = options
+	}
+}
+
+// WithDNS enables or disables DNS MX lookups
+func WithDNS(enabled bool) Option {
+	return func(o *Options) {
+		o.CheckDNS = enabled
+	}
+}
+
+// WithDNSTimeout sets the timeout for DNS lookups
+func WithDNSTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.DNSTimeout = timeout
+	}
+}
+
+// WithDNSCache sets the TTL and maximum number of entries for the DNS cache
+func WithDNSCache(ttl time.Duration, size int) Option {
+	return func(o *Options) {
+		o.DNSCacheTTL = ttl
+		o.DNSCacheSize = size
+	}
+}
+
+// WithDomainSyntaxCheck sets whether domains are checked label by label. It's on by
+// default, including for zero Options, because the address parser accepts domains
+// like "gmail..com" that can never receive mail. Turning it off leaves domain syntax
+// to the parser alone.
+func WithDomainSyntaxCheck(enabled bool) Option {
+	return func(o *Options) {
+		o.SkipDomainSyntaxCheck = !enabled
+	}
+}
+
+// WithDisposableURL enables disposable domain checking using the list at the given URL
+func WithDisposableURL(url string) Option {
+	return func(o *Options) {
+		o.CheckDisposable = true
+		o.DisposableDomainsURL = url
+	}
+}
+
+// WithFreeProvidersURL enables free provider checking using the list at the given URL
+func WithFreeProvidersURL(url string) Option {
+	return func(o *Options) {
+		o.CheckFreeProvider = true
+		o.FreeProvidersURL = url
+	}
+}
+
+// WithTrustedDomainsURL sets the URL for the trusted domains list
+func WithTrustedDomainsURL(url string) Option {
+	return func(o *Options) {
+		o.TrustedDomainsURL = url
+	}
+}
+
+// WithMaxConcurrency limits the number of concurrent validations in ValidateMany
+func WithMaxConcurrency(n int) Option {
+	return func(o *Options) {
+		o.MaxConcurrency = n
+	}
+}
+
+// WithMaxEmailLength sets the maximum email length
+func WithMaxEmailLength(n int) Option {
+	return func(o *Options) {
+		o.MaxEmailLength = n
+	}
+}
+
+// WithMinDomainLength sets the minimum domain length
+func WithMinDomainLength(n int) Option {
+	return func(o *Options) {
+		o.MinDomainLength = n
+	}
+}
+
+// WithRejectDisposable sets whether disposable domains are invalid. Enabling it
+// also enables disposable domain checking.
+func WithRejectDisposable(reject bool) Option {
+	return func(o *Options) {
+		o.RejectDisposable = reject
+		if reject {
+			o.CheckDisposable = true
+		}
+	}
+}
+
+// WithRejectFreeProvider sets whether free email providers are invalid. Enabling
+// it also enables free provider checking.
+func WithRejectFreeProvider(reject bool) Option {
+	return func(o *Options) {
+		o.RejectFreeProvider = reject
+		if reject {
+			o.CheckFreeProvider = true
+		}
+	}
+}
+
+// WithRejectIPDomains sets whether IP address domains are invalid
+func WithRejectIPDomains(reject bool) Option {
+	return func(o *Options) {
+		o.RejectIPDomains = reject
+	}
+}
+
+// WithRejectNamedEmails sets whether named email addresses are invalid
+func WithRejectNamedEmails(reject bool) Option {
+	return func(o *Options) {
+		o.RejectNamedEmails = reject
+	}
+}
+
+// WithRejectReserved sets whether reserved example domains are invalid
+func WithRejectReserved(reject bool) Option {
+	return func(o *Options) {
+		o.RejectReserved = reject
+	}
+}
+
+// WithSeverity sets what happens when a check flags an email, overriding its Reject*
+// option. Checks without a Reject* option can't be given a severity.
+func WithSeverity(check CheckType, severity Severity) Option {
+	return func(o *Options) {
+		// Copied so a ValidateWith override doesn't change the validator's own map
+		severities := make(map[CheckType]Severity, len(o.Severities)+1)
+		for c, s := range o.Severities {
+			severities[c] = s
+		}
+		severities[check] = severity
+		o.Severities = severities
+	}
+}
+
+// Options returns a snapshot of the validator's current options.
+func (v *Validator) Options() Options {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.options
+}
+
+// SetCheckDNS turns DNS MX lookups on or off at runtime, e.g. to stop depending on
+// DNS during an incident. Validations already in progress are not affected. When DNS
+// checks are off, RequireDNSSEC is not enforced.
+func (v *Validator) SetCheckDNS(enabled bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.options.CheckDNS = enabled
+}
+
+// SetDNSCacheTTL changes the TTL for cached DNS lookups at runtime, including entries
+// that are already cached. DNSNegativeCacheTTL is lowered to match if it's longer.
+func (v *Validator) SetDNSCacheTTL(ttl time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.options.DNSCacheTTL = ttl
+	if v.options.DNSNegativeCacheTTL > ttl {
+		v.options.DNSNegativeCacheTTL = ttl
+	}
+}
+
+// checkDNS reports whether DNS MX lookups are enabled
+func (v *Validator) checkDNS() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.options.CheckDNS
+}
+
+// ValidateWith checks a single email address with options overridden for this call
+// only, e.g. WithDNS(true) to look up one important address. The validator's own
+// options aren't changed, so other validations running at the same time aren't
+// affected. Lists, caches and stats are shared with the validator, but options only
+// used when building one, such as list URLs, Resolver, DNSCache, Logger and
+// CacheDomainVerdicts, have no effect (see ValidateWithResolver to use another
+// resolver). If the overrides are invalid, e.g. CheckReceivable without CheckDNS, the
+// result is invalid with the problem in LastError.
+func (v *Validator) ValidateWith(email string, overrides ...Option) ValidationResult {
+	if len(overrides) == 0 {
+		return v.Validate(email)
+	}
+
+	view, err := v.withOverrides(v.resolver, v.resolverScope, overrides)
+	if err != nil {
+		return v.invalidCall(email, err)
+	}
+	return view.Validate(email)
+}
+
+// ValidateWithResolver is like ValidateWith, but runs the DNS lookups for this call
+// through resolver instead of the validator's own, e.g. to pin which nameserver
+// validates a batch. The name identifies the resolver in the DNS cache: results are
+// cached per name, so lookups through one resolver never answer another's, and
+// calls using the same name share them. The resolver must support the lookups the
+// options need, as with Options.Resolver.
+func (v *Validator) ValidateWithResolver(email, name string, resolver Resolver, overrides ...Option) ValidationResult {
+	if name == "" || resolver == nil {
+		return v.invalidCall(email, fmt.Errorf("ValidateWithResolver requires a resolver and a name"))
+	}
+
+	view, err := v.withOverrides(resolver, name, overrides)
+	if err != nil {
+		return v.invalidCall(email, err)
+	}
+	return view.Validate(email)
+}
+
+// WithOptions returns a validator with different options that shares this one's
+// lists, DNS cache and stats, e.g. to A/B test a validation policy without reloading
+// large lists. Zero options are filled in from DefaultOptions as in New.
+//
+// As with ValidateWith, options only used when building a validator, such as list
+// URLs, MatchSubdomains, Resolver, DNSCache and Logger, have no effect; the clone
+// uses this validator's loaded lists and resolver. Because the lists and caches are
+// shared rather than copied, lists loaded or registered through either validator are
+// seen by both, DNS results cached by one answer the other, both count towards the
+// same Stats, and closing either closes both. The clone keeps its own domain verdict
+// cache, since verdicts depend on the options.
+func (v *Validator) WithOptions(options Options) (*Validator, error) {
+	clone, err := v.withOverrides(v.resolver, v.resolverScope, []Option{WithOptions(options)})
+	if err != nil {
+		return nil, err
+	}
+
+	if clone.options.CacheDomainVerdicts {
+		clone.verdicts = newLRUCache[domainVerdict](domainVerdictCacheSize)
+	}
+
+	// The TLD list is only loaded by New when CheckTLD is set, so load the bundled one
+	// for a clone that checks TLDs when this validator doesn't
+	if clone.options.CheckTLD {
+		v.mu.Lock()
+		if v.tlds == nil {
+			v.tlds = parseTLDList(defaultTLDList)
+		}
+		v.mu.Unlock()
+	}
+	return clone, nil
+}
+
+// invalidCall returns the result for a call that couldn't be made, such as one with
+// invalid overrides
+func (v *Validator) invalidCall(email string, err error) ValidationResult {
+	result := ValidationResult{Original: email, LastError: err}
+	v.maskResult(&result)
+	return result
+}
+
+// withOverrides returns a validator that shares v's state but runs with overrides
+// applied to a copy of its options, looking up DNS through resolver with cache keys
+// scoped by scope. It doesn't memoize verdicts, since they depend on the options.
+func (v *Validator) withOverrides(resolver Resolver, scope string, overrides []Option) (*Validator, error) {
+	v.mu.RLock()
+	options, allowed := v.options, v.allowedDomains
+	v.mu.RUnlock()
+
+	patterns := options.AllowedDomainPatterns
+	for _, override := range overrides {
+		override(&options)
+	}
+	options = mergeWithDefaults(options)
+
+	view := &Validator{resolver: resolver, resolverScope: scope, validatorState: v.validatorState}
+	if err := view.validateOptions(options); err != nil {
+		return nil, fmt.Errorf("invalid option overrides: %v", err)
+	}
+
+	if !slices.Equal(patterns, options.AllowedDomainPatterns) {
+		var err error
+		if allowed, err = compilePatterns(options.AllowedDomainPatterns); err != nil {
+			return nil, fmt.Errorf("invalid option overrides: %v", err)
+		}
+	}
+
+	view.options, view.allowedDomains = options, allowed
+	return view, nil
+}
+
+// EffectiveOptions returns opts with zero values filled in from DefaultOptions, as
+// used by New. It's useful for logging the configuration a validator will run with.
+func EffectiveOptions(opts Options) Options {
+	return mergeWithDefaults(opts)
+}
+
+// optionsJSON is the JSON form of Options. Durations are written as strings like "3s",
+// and CustomRules, Logger, OnListUpdate and the DNSCache and Resolver implementations are left out.
+type optionsJSON struct {
+	options
+	CustomRules         *struct{} `json:",omitempty"`
+	DNSCache            *struct{} `json:",omitempty"`
+	DNSCacheTTL         jsonDuration
+	DNSNegativeCacheTTL jsonDuration
+	DNSTimeout          jsonDuration
+	ListFetchRetryDelay jsonDuration
+	Logger              *struct{} `json:",omitempty"`
+	OnListUpdate        *struct{} `json:",omitempty"`
+	Resolver            *struct{} `json:",omitempty"`
+}
+
+// options has the fields of Options without its JSON methods
+type options Options
+
+// MarshalJSON encodes the options using Go field names as keys. Durations are written
+// as strings like "3s". CustomRules, DNSCache, Logger, OnListUpdate and Resolver can't be
+// encoded and are omitted.
+func (o Options) MarshalJSON() ([]byte, error) {
+	return json.Marshal(optionsJSON{
+		options:             options(o),
+		DNSCacheTTL:         jsonDuration(o.DNSCacheTTL),
+		DNSNegativeCacheTTL: jsonDuration(o.DNSNegativeCacheTTL),
+		DNSTimeout:          jsonDuration(o.DNSTimeout),
+		ListFetchRetryDelay: jsonDuration(o.ListFetchRetryDelay),
+	})
+}
+
+// UnmarshalJSON decodes options written by MarshalJSON. Durations may be strings like
+// "3s" or integer nanoseconds. Fields that are missing keep their current value, so
+// decoding into DefaultOptions() only overrides what the input sets.
+func (o *Options) UnmarshalJSON(data []byte) error {
+	aux := optionsJSON{
+		options:             options(*o),
+		DNSCacheTTL:         jsonDuration(o.DNSCacheTTL),
+		DNSNegativeCacheTTL: jsonDuration(o.DNSNegativeCacheTTL),
+		DNSTimeout:          jsonDuration(o.DNSTimeout),
+		ListFetchRetryDelay: jsonDuration(o.ListFetchRetryDelay),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	customRules, dnsCache, logger, onListUpdate, resolver := o.CustomRules, o.DNSCache, o.Logger, o.OnListUpdate, o.Resolver
+	*o = Options(aux.options)
+	o.CustomRules, o.DNSCache, o.Logger, o.OnListUpdate, o.Resolver = customRules, dnsCache, logger, onListUpdate, resolver
+	o.DNSCacheTTL = time.Duration(aux.DNSCacheTTL)
+	o.DNSNegativeCacheTTL = time.Duration(aux.DNSNegativeCacheTTL)
+	o.DNSTimeout = time.Duration(aux.DNSTimeout)
+	o.ListFetchRetryDelay = time.Duration(aux.ListFetchRetryDelay)
+	return nil
+}
+
+// jsonDuration is a time.Duration that encodes as a string like "3s"
+type jsonDuration time.Duration
+
+// MarshalJSON encodes the duration as a string like "1m30s"
+func (d jsonDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes a duration string like "1m30s" or integer nanoseconds
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var ns int64
+		if err := json.Unmarshal(data, &ns); err != nil {
+			return fmt.Errorf("invalid duration %s: must be a string like \"3s\" or nanoseconds", data)
+		}
+		*d = jsonDuration(ns)
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %v", err)
+	}
+	*d = jsonDuration(parsed)
+	return nil
+}
@@ -0,0 +1,343 @@
+package mailcop_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		v, err := mailcop.NewWithOptions()
+		require.NoError(t, err)
+		assert.True(t, v.Validate("user@company.org").IsValid)
+	})
+
+	t.Run("reject options", func(t *testing.T) {
+		v, err := mailcop.NewWithOptions(
+			mailcop.WithRejectReserved(true),
+			mailcop.WithRejectIPDomains(true),
+			mailcop.WithRejectNamedEmails(true),
+		)
+		require.NoError(t, err)
+
+		assert.False(t, v.Validate("user@example.com").IsValid)
+		assert.False(t, v.Validate("user@[127.0.0.1]").IsValid)
+		assert.False(t, v.Validate("John <john@company.org>").IsValid)
+		assert.True(t, v.Validate("john@company.org").IsValid)
+	})
+
+	t.Run("disposable URL", func(t *testing.T) {
+		v, err := mailcop.NewWithOptions(
+			mailcop.WithDisposableURL("file://"+filepath.Join("testdata", "domains.json")),
+			mailcop.WithRejectDisposable(true),
+		)
+		require.NoError(t, err)
+
+		result := v.Validate("user@tempmail.com")
+		assert.True(t, result.IsDisposable)
+		assert.False(t, result.IsValid)
+	})
+
+	t.Run("later options override WithOptions", func(t *testing.T) {
+		base := mailcop.DefaultOptions()
+		base.RejectReserved = true
+
+		v, err := mailcop.NewWithOptions(
+			mailcop.WithOptions(base),
+			mailcop.WithMaxEmailLength(20),
+		)
+		require.NoError(t, err)
+
+		assert.False(t, v.Validate("user@example.com").IsValid)
+		assert.False(t, v.Validate("a-very-long-user@company.org").IsValid)
+	})
+
+	t.Run("max concurrency", func(t *testing.T) {
+		v, err := mailcop.NewWithOptions(mailcop.WithMaxConcurrency(2))
+		require.NoError(t, err)
+
+		emails := []string{"a@company.org", "b@company.org", "c@company.org", "invalid@"}
+		assert.Len(t, v.ValidateMany(emails), len(emails))
+	})
+}
+
+func TestLiveOptions(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.DNSCacheTTL = time.Hour
+	opts.DNSNegativeCacheTTL = 5 * time.Minute
+	opts.Resolver = staticResolver{"company.org": {"mx.company.org."}}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	result := v.Validate("user@unresolvable.org")
+	assert.False(t, result.IsValid, "unresolvable domains fail while DNS checks are on")
+
+	v.SetCheckDNS(false)
+	assert.False(t, v.Options().CheckDNS)
+
+	result = v.Validate("user@unresolvable.org")
+	assert.True(t, result.IsValid)
+	assert.False(t, result.HasMX)
+
+	v.SetCheckDNS(true)
+	assert.True(t, v.Validate("user@company.org").HasMX)
+
+	v.SetDNSCacheTTL(time.Minute)
+	snapshot := v.Options()
+	assert.Equal(t, time.Minute, snapshot.DNSCacheTTL)
+	assert.Equal(t, time.Minute, snapshot.DNSNegativeCacheTTL, "negative TTL should not exceed the new TTL")
+
+	// Changing a snapshot doesn't affect the validator
+	snapshot.CheckDNS = false
+	assert.True(t, v.Options().CheckDNS)
+
+	t.Run("concurrent toggling", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				v.Validate("user@company.org")
+			}()
+			go func(enabled bool) {
+				defer wg.Done()
+				v.SetCheckDNS(enabled)
+				v.SetDNSCacheTTL(time.Duration(i+1) * time.Minute)
+			}(i%2 == 0)
+		}
+		wg.Wait()
+	})
+}
+
+func TestValidateWith(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.Resolver = staticResolver{"company.org": {"mx.company.org."}}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	result := v.ValidateWith("user@unresolvable.org", mailcop.WithDNS(true))
+	assert.False(t, result.IsValid)
+	assert.Equal(t, mailcop.ReasonDNS, result.Reason)
+	assert.True(t, v.ValidateWith("user@company.org", mailcop.WithDNS(true)).HasMX)
+
+	// The validator's own options are unchanged
+	assert.False(t, v.Options().CheckDNS)
+	assert.True(t, v.Validate("user@unresolvable.org").IsValid)
+	assert.True(t, v.ValidateWith("user@unresolvable.org").IsValid)
+
+	t.Run("allowed domain patterns", func(t *testing.T) {
+		allowAcme := func(o *mailcop.Options) {
+			o.AllowedDomainPatterns = []string{"*.acme.com"}
+		}
+
+		assert.Equal(t, mailcop.ReasonDomainNotAllowed, v.ValidateWith("user@company.org", allowAcme).Reason)
+		assert.True(t, v.ValidateWith("user@mail.acme.com", allowAcme).IsValid)
+		assert.True(t, v.Validate("user@company.org").IsValid)
+	})
+
+	t.Run("invalid overrides", func(t *testing.T) {
+		result := v.ValidateWith("user@company.org", func(o *mailcop.Options) {
+			o.CheckReceivable = true
+		})
+		assert.False(t, result.IsValid)
+		assert.ErrorContains(t, result.LastError, "CheckReceivable requires CheckDNS")
+
+		result = v.ValidateWith("user@company.org", func(o *mailcop.Options) {
+			o.AllowedDomainPatterns = []string{"/[/"}
+		})
+		assert.False(t, result.IsValid)
+		assert.Error(t, result.LastError)
+	})
+
+	t.Run("concurrent calls", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				assert.False(t, v.ValidateWith("user@unresolvable.org", mailcop.WithDNS(true)).IsValid)
+			}()
+			go func() {
+				defer wg.Done()
+				assert.True(t, v.Validate("user@unresolvable.org").IsValid)
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestValidatorWithOptions(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+	opts.CheckDNS = true
+	opts.DNSCacheTTL = time.Hour
+	opts.Resolver = staticResolver{
+		"company.org":     {"mx.company.org."},
+		"company.notatld": {"mx.company.notatld."},
+		"tempmail.com":    {"mx.tempmail.com."},
+		"throwaway.org":   {"mx.throwaway.org."},
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	strict := opts
+	strict.RejectDisposable = true
+	strict.CheckTLD = true
+	strict.RejectUnknownTLD = true
+	strict.DisposableDomainsURL = "file:///nonexistent.json" // Not loaded again
+	clone, err := v.WithOptions(strict)
+	require.NoError(t, err)
+
+	t.Run("options differ", func(t *testing.T) {
+		assert.True(t, v.Validate("user@tempmail.com").IsValid)
+		assert.False(t, clone.Validate("user@tempmail.com").IsValid)
+		assert.True(t, v.Validate("user@company.notatld").IsValid)
+		assert.Equal(t, mailcop.ReasonUnknownTLD, clone.Validate("user@company.notatld").Reason)
+		assert.False(t, v.Options().RejectDisposable)
+	})
+
+	t.Run("lists are shared", func(t *testing.T) {
+		v.RegisterDisposableDomains([]string{"throwaway.org"})
+		assert.False(t, clone.Validate("user@throwaway.org").IsValid)
+	})
+
+	t.Run("DNS cache is shared", func(t *testing.T) {
+		require.True(t, v.Validate("user@company.org").IsValid)
+		assert.True(t, clone.Validate("user@company.org").DNSCacheHit)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		invalid := opts
+		invalid.CheckDNS = false
+		invalid.CheckReceivable = true
+		_, err := v.WithOptions(invalid)
+		assert.ErrorContains(t, err, "CheckReceivable requires CheckDNS")
+	})
+}
+
+func TestValidateWithResolver(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.Resolver = staticResolver{"company.org": {"mx.company.org."}}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	ns2 := staticResolver{"other.org": {"mx.other.org."}}
+
+	// Each resolver's answers are cached separately
+	assert.True(t, v.Validate("user@company.org").HasMX)
+	assert.False(t, v.ValidateWithResolver("user@company.org", "ns2", ns2).IsValid)
+	assert.True(t, v.ValidateWithResolver("user@other.org", "ns2", ns2).HasMX)
+	assert.False(t, v.Validate("user@other.org").IsValid)
+	assert.True(t, v.Validate("user@company.org").HasMX)
+
+	// Calls using the same name share cached results
+	hits := v.Stats().DNSCacheHits
+	result := v.ValidateWithResolver("user@other.org", "ns2", staticResolver{})
+	assert.True(t, result.HasMX)
+	assert.Equal(t, hits+1, v.Stats().DNSCacheHits)
+
+	t.Run("overrides", func(t *testing.T) {
+		result := v.ValidateWithResolver("user@other.org", "ns2", ns2, mailcop.WithDNS(false))
+		assert.True(t, result.IsValid)
+		assert.False(t, result.HasMX)
+	})
+
+	t.Run("invalid calls", func(t *testing.T) {
+		result := v.ValidateWithResolver("user@other.org", "", ns2)
+		assert.False(t, result.IsValid)
+		assert.ErrorContains(t, result.LastError, "requires a resolver and a name")
+
+		result = v.ValidateWithResolver("user@other.org", "ns2", ns2, func(o *mailcop.Options) {
+			o.CheckSPFExists = true
+		})
+		assert.False(t, result.IsValid)
+		assert.ErrorContains(t, result.LastError, "CheckSPFExists requires a TXTResolver")
+	})
+}
+
+func TestOptionsJSON(t *testing.T) {
+	t.Run("durations are strings", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.Resolver = staticResolver{}
+
+		data, err := json.Marshal(opts)
+		require.NoError(t, err)
+
+		var raw map[string]any
+		require.NoError(t, json.Unmarshal(data, &raw))
+		assert.Equal(t, "1h0m0s", raw["DNSCacheTTL"])
+		assert.Equal(t, "5m0s", raw["DNSNegativeCacheTTL"])
+		assert.Equal(t, "3s", raw["DNSTimeout"])
+		assert.Equal(t, "500ms", raw["ListFetchRetryDelay"])
+		assert.Equal(t, false, raw["PreserveDomainCase"])
+		assert.NotContains(t, raw, "Resolver")
+		assert.NotContains(t, raw, "DNSCache")
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.CheckDNS = true
+		opts.DNSTimeout = 1500 * time.Millisecond
+		opts.ListFetchRetryDelay = 2 * time.Second
+		opts.AllowedDomainPatterns = []string{"*.acme.com"}
+
+		data, err := json.Marshal(opts)
+		require.NoError(t, err)
+
+		var decoded mailcop.Options
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, opts, decoded)
+	})
+
+	t.Run("durations as strings or nanoseconds", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		err := json.Unmarshal([]byte(`{"CheckDNS": true, "DNSTimeout": "500ms", "DNSCacheTTL": 60000000000, "ListFetchRetryDelay": "1s"}`), &opts)
+		require.NoError(t, err)
+
+		assert.True(t, opts.CheckDNS)
+		assert.Equal(t, 500*time.Millisecond, opts.DNSTimeout)
+		assert.Equal(t, time.Minute, opts.DNSCacheTTL)
+		assert.Equal(t, time.Second, opts.ListFetchRetryDelay)
+		assert.Equal(t, 5*time.Minute, opts.DNSNegativeCacheTTL, "missing fields keep their value")
+		assert.Equal(t, 254, opts.MaxEmailLength)
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		var opts mailcop.Options
+		assert.Error(t, json.Unmarshal([]byte(`{"DNSTimeout": "soon"}`), &opts))
+		assert.Error(t, json.Unmarshal([]byte(`{"DNSTimeout": true}`), &opts))
+	})
+
+	t.Run("resolver is kept when decoding", func(t *testing.T) {
+		resolver := staticResolver{}
+		opts := mailcop.Options{Resolver: resolver}
+		require.NoError(t, json.Unmarshal([]byte(`{"CheckDNS": true}`), &opts))
+		assert.Equal(t, resolver, opts.Resolver)
+	})
+}
+
+func TestEffectiveOptions(t *testing.T) {
+	effective := mailcop.EffectiveOptions(mailcop.Options{CheckDNS: true, DNSCacheTTL: time.Minute})
+
+	assert.True(t, effective.CheckDNS)
+	assert.Equal(t, time.Minute, effective.DNSCacheTTL)
+	assert.Equal(t, time.Minute, effective.DNSNegativeCacheTTL)
+	assert.Equal(t, 3*time.Second, effective.DNSTimeout)
+	assert.Equal(t, 254, effective.MaxEmailLength)
+	assert.Equal(t, mailcop.DefaultScoreWeights(), effective.ScoreWeights)
+}
@@ -1,52 +1,75 @@
 package mailcop
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 )
 
-// RegisterFreeProviders manually adds domains to the free providers list
+// RegisterFreeProviders manually adds domains to the free providers list. Patterns like
+// "yahoo.*" match any regional variant when FreeProviderMatchVariants is enabled.
 func (v *Validator) RegisterFreeProviders(providers []string) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
+	defer v.invalidateVerdicts()
 
 	for _, provider := range providers {
-		v.freeProviders[provider] = struct{}{}
+		v.addFreeProvider(provider)
 	}
 }
 
-// RegisterDisposableDomains adds domains to either the map or bloom filter
+// RegisterDisposableDomains adds domains to either the map or bloom filter. Their
+// source is reported as "registered".
 func (v *Validator) RegisterDisposableDomains(domains []string) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
+	defer v.invalidateVerdicts()
 
 	if v.bloomFilter != nil {
 		for _, domain := range domains {
-			v.bloomFilter.Add([]byte(domain))
+			v.addToBloomFilter(domain)
 		}
 	} else {
 		for _, domain := range domains {
-			v.disposableDomains[domain] = struct{}{}
+			v.addDisposableDomain(domain, registeredSource)
 		}
 	}
 }
 
+// RegisterDisposableMXHosts adds MX hosts used by disposable email services. Domains
+// whose MX records point to one of these hosts are flagged as disposable even when the
+// domain itself isn't in a disposable list. Entries starting with "*." match any subdomain.
+func (v *Validator) RegisterDisposableMXHosts(hosts []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, host := range hosts {
+		v.disposableMX[normalizeDomain(host)] = struct{}{}
+	}
+}
+
 // RegisterTrustedDomains adds trusted domains that are never considered disposable
 func (v *Validator) RegisterTrustedDomains(domains []string) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
+	defer v.invalidateVerdicts()
 
 	if v.trustedDomains == nil {
 		v.trustedDomains = make(map[string]struct{})
 	}
 
 	for _, domain := range domains {
-		v.trustedDomains[domain] = struct{}{}
+		v.trustedDomains[normalizeDomain(domain)] = struct{}{}
 	}
 }
 
@@ -57,26 +80,145 @@ func (v *Validator) LoadDisposableDomains(urlStr string) error {
 		return nil
 	}
 
-	providers, err := v.loadProviderList(urlStr)
+	providers, updated, err := v.loadDomainList(urlStr)
 	if err != nil {
 		return fmt.Errorf("failed to load disposable domains: %v", err)
 	}
 
+	update, reloaded := v.storeDisposableList(urlStr, providers, updated)
+	v.logger.Debug("list refreshed", "list", "disposable", "url", urlStr, "count", len(providers))
+
+	// Called without the lock held, so the callback can use the validator
+	if reloaded && v.options.OnListUpdate != nil {
+		v.options.OnListUpdate(update)
+	}
+	return nil
+}
+
+// storeDisposableList adds the domains of a disposable list to either the bloom filter
+// or the map. It reports what changed since the list's last load, if it was loaded before.
+func (v *Validator) storeDisposableList(urlStr string, providers []string, updated time.Time) (ListUpdate, bool) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
+	defer v.invalidateVerdicts()
+
+	v.disposableUpdated = updated
+
+	list := make(map[string]struct{}, len(providers))
+	for _, provider := range providers {
+		if domain := normalizeDomain(provider); domain != "" {
+			list[domain] = struct{}{}
+		}
+	}
+
+	previous, reloaded := v.disposableListSizes[urlStr]
+	v.disposableListSizes[urlStr] = len(list)
+	update := ListUpdate{URL: urlStr, Count: len(list), Previous: previous}
 
 	// Add domains to either bloom filter or map
 	if v.bloomFilter != nil {
-		for _, provider := range providers {
-			v.bloomFilter.Add([]byte(provider))
+		for domain := range list {
+			v.addToBloomFilter(domain)
 		}
-	} else {
-		for _, provider := range providers {
-			v.disposableDomains[provider] = struct{}{}
+		return update, reloaded
+	}
+
+	// Keeping each list to diff against costs memory, so it's only done when needed
+	if v.options.OnListUpdate != nil {
+		if reloaded {
+			update.Added, update.Removed = diffDomains(v.disposableLists[urlStr], list)
 		}
+		v.disposableLists[urlStr] = list
 	}
+	for domain := range list {
+		v.addDisposableDomain(domain, urlStr)
+	}
+	return update, reloaded
+}
 
-	return nil
+// DisposableSource returns the URL of the list a disposable domain was loaded from,
+// or "registered" for domains added with RegisterDisposableDomains. When a domain
+// appears in several lists, the first source is kept. Sources aren't tracked when
+// using a bloom filter.
+func (v *Validator) DisposableSource(domain string) (string, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	source, ok := v.disposableSources[domain]
+	return source, ok
+}
+
+// IsDisposableDomain reports whether a domain is disposable, exactly as validation
+// would decide it: trusted domains are never disposable, and it's always false when
+// CheckDisposable is off. With a bloom filter the answer is probabilistic; a false
+// result is certain, but a true result may be a false positive. Mail server matches
+// from RegisterDisposableMXHosts aren't considered, since they need an MX lookup.
+func (v *Validator) IsDisposableDomain(domain string) bool {
+	return v.isDisposable(normalizeDomain(domain))
+}
+
+// DisposableDomains returns a sorted copy of the loaded disposable domains, e.g. for
+// exporting or diffing the list. It returns false when a bloom filter is in use, since
+// its domains can't be enumerated.
+func (v *Validator) DisposableDomains() ([]string, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.bloomFilter != nil {
+		return nil, false
+	}
+	return sortedKeys(v.disposableDomains), true
+}
+
+// disposableSourceCount returns the number of distinct sources listing a disposable
+// domain. Bloom filter matches always count as one source.
+func (v *Validator) disposableSourceCount(domain string) int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if _, ok := v.disposableSources[domain]; !ok {
+		return 1
+	}
+	return 1 + len(v.disposableAlsoIn[domain])
+}
+
+// DisposableListLastUpdated returns when the disposable domains were last loaded from
+// a list: the list's Last-Modified time when it was served over HTTP with that header,
+// or the time it was fetched otherwise. It's zero if no list has been loaded.
+func (v *Validator) DisposableListLastUpdated() time.Time {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.disposableUpdated
+}
+
+// FreeProvidersLastUpdated returns when the free providers were last loaded from a
+// list, like DisposableListLastUpdated. It's zero if no list has been loaded.
+func (v *Validator) FreeProvidersLastUpdated() time.Time {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.freeProvidersUpdated
+}
+
+// registeredSource is the source reported for manually registered disposable domains
+const registeredSource = "registered"
+
+// addDisposableDomain adds a domain to the disposable map, recording where it came
+// from unless it's already known. The caller must hold the write lock.
+func (v *Validator) addDisposableDomain(domain, source string) {
+	domain = normalizeDomain(domain)
+	v.disposableDomains[domain] = struct{}{}
+	if v.disposableTrie != nil {
+		v.disposableTrie.insert(domain)
+	}
+	first, ok := v.disposableSources[domain]
+	switch {
+	case !ok:
+		v.disposableSources[domain] = source
+	case source != first && !slices.Contains(v.disposableAlsoIn[domain], source):
+		v.disposableAlsoIn[domain] = append(v.disposableAlsoIn[domain], source)
+	}
 }
 
 // LoadFreeProviders loads a list of free email providers from a JSON file or URL
@@ -85,18 +227,22 @@ func (v *Validator) LoadFreeProviders(urlStr string) error {
 		return nil
 	}
 
-	providers, err := v.loadProviderList(urlStr)
+	providers, updated, err := v.loadDomainList(urlStr)
 	if err != nil {
 		return fmt.Errorf("failed to load free providers: %v", err)
 	}
 
 	v.mu.Lock()
 	defer v.mu.Unlock()
+	defer v.invalidateVerdicts()
+
+	v.freeProvidersUpdated = updated
 
 	for _, provider := range providers {
-		v.freeProviders[provider] = struct{}{}
+		v.addFreeProvider(provider)
 	}
 
+	v.logger.Debug("list refreshed", "list", "free_providers", "url", urlStr, "count", len(providers))
 	return nil
 }
 
@@ -106,65 +252,186 @@ func (v *Validator) LoadTrustedDomains(urlStr string) error {
 		return nil
 	}
 
-	providers, err := v.loadProviderList(urlStr)
+	providers, _, err := v.loadDomainList(urlStr)
 	if err != nil {
 		return fmt.Errorf("failed to load trusted domains: %v", err)
 	}
 
 	v.mu.Lock()
 	defer v.mu.Unlock()
+	defer v.invalidateVerdicts()
 
 	for _, provider := range providers {
-		v.trustedDomains[provider] = struct{}{}
+		v.trustedDomains[normalizeDomain(provider)] = struct{}{}
 	}
 
+	v.logger.Debug("list refreshed", "list", "trusted", "url", urlStr, "count", len(providers))
 	return nil
 }
 
-// loadProviderList loads a list of email providers from a JSON file or URL
-func (v *Validator) loadProviderList(urlStr string) ([]string, error) {
-	parsedURL, err := url.Parse(urlStr)
+// loadProviderList loads a list of email providers from a JSON or text file or URL,
+// along with when the list was last updated (see readListSource). The list may be
+// gzipped, or a zip archive of several lists that are merged (see parseZipList).
+// Text lists are detected by isTextList.
+func (v *Validator) loadProviderList(urlStr string) ([]string, time.Time, error) {
+	start := time.Now()
+	data, updated, err := v.readListSource(urlStr)
+	if err == nil {
+		data, err = gunzipList(data)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("invalid URL: %v", err)
+		v.logger.Debug("list fetch failed", "url", urlStr, "error", err, "duration", time.Since(start))
+		return nil, time.Time{}, err
 	}
 
-	var data []byte
-	if parsedURL.Scheme == "file" {
-		// Load from file
-		data, err = os.ReadFile(strings.TrimPrefix(urlStr, "file://"))
-		if err != nil {
-			return nil, fmt.Errorf("failed to read file: %v", err)
+	var providers []string
+	switch {
+	case isZipList(urlStr, data):
+		providers, err = parseZipList(data)
+	case isTextList(urlStr, data):
+		providers, err = parseTextList(data)
+	default:
+		if providers, err = parseProviderList(data); err != nil {
+			err = fmt.Errorf("failed to parse JSON: %v", err)
 		}
-	} else {
-		// Load from URL
-		resp, err := http.Get(urlStr)
-		if err != nil {
-			return nil, err
+	}
+	if err != nil {
+		v.logger.Debug("list fetch failed", "url", urlStr, "error", err, "duration", time.Since(start))
+		return nil, time.Time{}, err
+	}
+
+	v.logger.Debug("list fetched", "url", urlStr, "count", len(providers), "duration", time.Since(start))
+	return providers, updated, nil
+}
+
+// loadDomainList loads a list of domains like loadProviderList, normalizing each entry
+// so that messy sources still match: surrounding whitespace, a "mailto:" prefix and a
+// trailing dot are removed, the domain is lowercased and empty entries are dropped.
+// Lists of patterns and local parts use loadProviderList, since they aren't domains.
+func (v *Validator) loadDomainList(urlStr string) ([]string, time.Time, error) {
+	entries, updated, err := v.loadProviderList(urlStr)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	domains := entries[:0]
+	for _, entry := range entries {
+		if domain := normalizeDomain(strings.TrimPrefix(normalizeDomain(entry), "mailto:")); domain != "" {
+			domains = append(domains, domain)
 		}
-		defer func(Body io.ReadCloser) {
-			_ = Body.Close()
-		}(resp.Body)
+	}
+	return domains, updated, nil
+}
 
-		decoder := json.NewDecoder(resp.Body)
+// parseProviderList parses a JSON list of domains. It accepts a flat array, an object
+// with a "domains" array, or an object keyed by domain like the index.json of the
+// disposable-email-domains project.
+func parseProviderList(data []byte) ([]string, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
 		var providers []string
-		if err := decoder.Decode(&providers); err != nil {
+		if err := json.Unmarshal(data, &providers); err != nil {
 			return nil, err
 		}
 		return providers, nil
 	}
 
-	var providers []string
-	if err := json.Unmarshal(data, &providers); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %v", err)
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
 	}
 
+	if raw, ok := object["domains"]; ok {
+		var providers []string
+		if err := json.Unmarshal(raw, &providers); err == nil {
+			return providers, nil
+		}
+	}
+
+	providers := make([]string, 0, len(object))
+	for domain := range object {
+		providers = append(providers, domain)
+	}
+	sort.Strings(providers)
 	return providers, nil
 }
 
+// readListSource reads the raw contents of a list from a file:// URL or a remote URL.
+// It also returns when the list was last updated: the Last-Modified header of a remote
+// list when the server sends one, or the fetch time otherwise. Remote fetches that fail
+// with a network error or a 5xx status are retried up to ListFetchRetries times, with
+// the delay starting at ListFetchRetryDelay and doubling after each attempt.
+func (v *Validator) readListSource(urlStr string) ([]byte, time.Time, error) {
+	parsedURL, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid URL: %v", err)
+	}
+
+	if parsedURL.Scheme == "file" {
+		// Load from file
+		data, err := os.ReadFile(strings.TrimPrefix(urlStr, "file://"))
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to read file: %v", err)
+		}
+		return data, time.Now(), nil
+	}
+
+	delay := v.options.ListFetchRetryDelay
+	for attempt := 1; ; attempt++ {
+		data, updated, retry, err := fetchList(urlStr)
+		if err == nil || !retry || attempt > v.options.ListFetchRetries {
+			return data, updated, err
+		}
+
+		v.logger.Debug("list fetch retry", "url", urlStr, "attempt", attempt, "error", err, "delay", delay)
+		select {
+		case <-time.After(delay):
+		case <-v.done:
+			return nil, time.Time{}, err
+		}
+		delay *= 2
+	}
+}
+
+// fetchList fetches a remote list, reporting whether a failure is transient and worth
+// retrying: network errors and 5xx statuses are, other statuses aren't
+func fetchList(urlStr string) ([]byte, time.Time, bool, error) {
+	fetched := time.Now()
+	resp, err := http.Get(urlStr)
+	if err != nil {
+		return nil, time.Time{}, true, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, time.Time{}, resp.StatusCode >= 500, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	data, err := readList(resp.Body, maxListSize)
+	if err != nil {
+		return nil, time.Time{}, !errors.Is(err, errListTooLarge), err
+	}
+
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		return data, modified, false, nil
+	}
+	return data, fetched, false, nil
+}
+
 // isDisposable checks if a domain is disposable using either implementation
 func (v *Validator) isDisposable(domain string) bool {
+	_, ok := v.matchDisposable(domain)
+	return ok
+}
+
+// matchDisposable checks if a domain is disposable and returns the listed domain it
+// matched, which is a parent of the domain when MatchSubdomains is set. The listed
+// domain is empty for bloom filter matches.
+func (v *Validator) matchDisposable(domain string) (string, bool) {
 	if !v.options.CheckDisposable {
-		return false
+		return "", false
 	}
 
 	v.mu.RLock()
@@ -172,30 +439,68 @@ func (v *Validator) isDisposable(domain string) bool {
 
 	// Check trusted domains first
 	if _, ok := v.trustedDomains[domain]; ok {
-		return false
+		return "", false
 	}
 
 	// If using bloom filter
 	if v.bloomFilter != nil {
-		// First check trusted domains (whitelist)
-		if _, ok := v.disposableDomains[domain]; ok {
-			return false
+		// Every verification filter must match to reduce false positives
+		if !v.testBloomFilter(domain) {
+			return "", false // Definitely not disposable
 		}
 
-		// Do multiple checks to reduce false positives
-		attempts := v.bloomOptions.VerificationAttempts
-		for i := 0; i < attempts; i++ {
-			if !v.bloomFilter.Test([]byte(domain)) {
-				return false // Definitely not disposable
-			}
-		}
+		return "", true // Probably disposable
+	}
 
-		return true // Probably disposable
+	if v.disposableTrie != nil {
+		return v.disposableTrie.match(domain)
 	}
 
 	// Original map implementation
 	_, exists := v.disposableDomains[domain]
-	return exists
+	return domain, exists
+}
+
+// isDisposableMX checks if any MX host of a domain belongs to a disposable service and
+// returns the matching host. Records are only available when CheckDNS is enabled.
+func (v *Validator) isDisposableMX(domain string, records []*net.MX) (string, bool) {
+	if !v.options.CheckDisposable || len(records) == 0 {
+		return "", false
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if len(v.disposableMX) == 0 {
+		return "", false
+	}
+
+	if _, ok := v.trustedDomains[domain]; ok {
+		return "", false
+	}
+
+	for _, record := range records {
+		host := normalizeDomain(record.Host)
+		if _, ok := v.disposableMX[host]; ok {
+			return host, true
+		}
+
+		// Check wildcard entries against each parent domain of the host
+		for rest := host; strings.Contains(rest, "."); {
+			rest = rest[strings.Index(rest, ".")+1:]
+			if _, ok := v.disposableMX["*."+rest]; ok {
+				return host, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// normalizeDomain lowercases a domain or hostname and removes the trailing dot of a
+// fully-qualified name, so "Gmail.Com." and "gmail.com" compare equal
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
 }
 
 // Add helper method for free provider detection
@@ -207,6 +512,52 @@ func (v *Validator) isFreeProvider(domain string) bool {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 
-	_, isFree := v.freeProviders[domain]
+	if _, isFree := v.freeProviders[domain]; isFree {
+		return true
+	}
+
+	if !v.options.FreeProviderMatchVariants {
+		return false
+	}
+
+	base := variantBase(domain)
+	if base == "" {
+		return false
+	}
+	_, isFree := v.freeProviderBases[base]
 	return isFree
 }
+
+// addFreeProvider adds a free provider and indexes its base name for variant
+// matching. The caller must hold the write lock.
+func (v *Validator) addFreeProvider(provider string) {
+	provider = normalizeDomain(provider)
+	v.freeProviders[provider] = struct{}{}
+	if base := variantBase(provider); base != "" {
+		v.freeProviderBases[base] = struct{}{}
+	}
+}
+
+// secondLevelLabels are common second-level labels of country-code suffixes like "co.uk"
+var secondLevelLabels = map[string]struct{}{
+	"ac": {}, "co": {}, "com": {}, "edu": {}, "gov": {}, "ne": {}, "net": {}, "or": {}, "org": {},
+}
+
+// variantBase returns a domain without its suffix, so "yahoo.com", "yahoo.co.uk" and the
+// pattern "yahoo.*" all return "yahoo". It returns "" if the domain has no suffix to strip.
+func variantBase(domain string) string {
+	i := strings.LastIndex(domain, ".")
+	if i <= 0 {
+		return ""
+	}
+	base := domain[:i]
+
+	// Strip a second-level label when it's part of the suffix, as in "co.uk"
+	if j := strings.LastIndex(base, "."); j > 0 && !strings.HasSuffix(domain, ".*") {
+		if _, ok := secondLevelLabels[base[j+1:]]; ok {
+			base = base[:j]
+		}
+	}
+
+	return base
+}
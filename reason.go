@@ -0,0 +1,38 @@
+package mailcop
+
+// Reason is a machine-readable code for why a validation failed. Unlike LastError,
+// it never contains the address or domain, so it's suitable for grouping and metrics.
+type Reason string
+
+// Failure reasons reported in ValidationResult.Reason. Valid results have no reason.
+const (
+	ReasonSyntax           Reason = "syntax"             // The input isn't a well-formed address, domain or address list
+	ReasonTooLong          Reason = "too_long"           // The address or one of its parts exceeds a length limit
+	ReasonNamed            Reason = "named"              // The address has a display name and RejectNamedEmails is set
+	ReasonDisplayNameSpoof Reason = "display_name_spoof" // The display name contains an address at another domain and RejectDisplayNameSpoof is set
+	ReasonNonASCII         Reason = "non_ascii"          // The address has non-ASCII characters and ASCIIOnly is set
+	ReasonUTF8LocalPart    Reason = "utf8_local_part"    // The local part is non-ASCII and RejectUTF8LocalPart is set
+	ReasonBannedLocalPart  Reason = "banned_local_part"  // The local part is on the banned list
+	ReasonDomainTooShort   Reason = "domain_too_short"   // The domain is shorter than MinDomainLength
+	ReasonTooManyLabels    Reason = "too_many_labels"    // The domain has more labels than MaxDomainLabels
+	ReasonDomainNotAllowed Reason = "domain_not_allowed" // The domain doesn't match AllowedDomainPatterns
+	ReasonPublicSuffix     Reason = "public_suffix"      // The domain is a public suffix and RejectPublicSuffixDomains is set
+	ReasonInvalidIDN       Reason = "invalid_idn"        // The internationalized domain doesn't round-trip and RejectInvalidIDN is set
+	ReasonIPDomain         Reason = "ip_domain"          // The domain is an IP address and RejectIPDomains is set
+	ReasonNoReverseDNS     Reason = "no_reverse_dns"     // The domain is an IP address without a PTR record and RequireIPReverseDNS is set
+	ReasonInvalidTLD       Reason = "invalid_tld"        // The top-level domain is missing or malformed
+	ReasonUnknownTLD       Reason = "unknown_tld"        // The top-level domain isn't in the TLD list
+	ReasonReserved         Reason = "reserved"           // The domain is reserved and RejectReserved is set
+	ReasonDisposable       Reason = "disposable"         // The domain or its mail server is disposable and RejectDisposable is set
+	ReasonFreeProvider     Reason = "free_provider"      // The domain is a free provider and RejectFreeProvider is set
+	ReasonDNS              Reason = "dns"                // The MX lookup failed
+	ReasonNullMX           Reason = "null_mx"            // The domain publishes a null MX record
+	ReasonNotReceivable    Reason = "not_receivable"     // The domain has no MX records and CheckReceivable is set
+	ReasonNoDMARC          Reason = "no_dmarc"           // The domain has no DMARC record and RejectNoDMARC is set
+	ReasonDNSSEC           Reason = "dnssec"             // The MX records aren't DNSSEC-validated and RequireDNSSEC is set
+	ReasonMailProvider     Reason = "mail_provider"      // No MX host matches RequiredMXSuffixes
+	ReasonTooFewMX         Reason = "too_few_mx"         // The domain has fewer distinct MX hosts than MinMXRecords
+	ReasonPrivateIP        Reason = "private_ip"         // The domain has no MX records, resolves to a private address and RejectPrivateIPResolution is set
+	ReasonCustomRule       Reason = "custom_rule"        // One of the CustomRules returned an error
+	ReasonTimeout          Reason = "timeout"            // ValidateWithTimeout gave up before validation finished
+)
@@ -0,0 +1,116 @@
+package mailcop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// ErrNotReceivable is returned in ValidationResult.LastError when CheckReceivable is
+// set and the domain has no MX records, even if it resolves to an address. ErrNullMX
+// wraps it, so it also matches domains that publish a null MX record.
+var ErrNotReceivable = errors.New("domain does not accept mail")
+
+// ErrNullMX is returned in ValidationResult.LastError when DNS checks are enabled and
+// the domain publishes a null MX record. It wraps ErrNotReceivable.
+var ErrNullMX = fmt.Errorf("%w: null MX record", ErrNotReceivable)
+
+// ErrTooFewMX is returned in ValidationResult.LastError when MinMXRecords is set and
+// the domain has fewer distinct MX hosts
+var ErrTooFewMX = errors.New("too few MX records")
+
+// ErrPrivateIPResolution is returned in ValidationResult.LastError when
+// RejectPrivateIPResolution is set and a domain without MX records resolves to a private,
+// loopback or link-local address
+var ErrPrivateIPResolution = errors.New("domain resolves to a private address")
+
+// isNullMX reports whether MX records are a null MX (RFC 7505): a single record
+// whose target is ".", declaring that the domain accepts no mail
+func isNullMX(records []*net.MX) bool {
+	return len(records) == 1 && (records[0].Host == "." || records[0].Host == "")
+}
+
+// appendMXHosts appends the hosts of MX records to hosts in the order given, without
+// trailing dots. A null MX has no hosts.
+func appendMXHosts(hosts []string, records []*net.MX) []string {
+	if isNullMX(records) {
+		return hosts
+	}
+	for _, record := range records {
+		hosts = append(hosts, strings.TrimSuffix(record.Host, "."))
+	}
+	return hosts
+}
+
+// distinctHosts counts the distinct hosts, compared case-insensitively
+func distinctHosts(hosts []string) int {
+	seen := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		seen[strings.ToLower(host)] = struct{}{}
+	}
+	return len(seen)
+}
+
+// canReceiveMail reports whether MX records point to at least one real mail server
+func canReceiveMail(records []*net.MX) bool {
+	return len(records) > 0 && !isNullMX(records)
+}
+
+// isNotFound reports whether a lookup failed because the domain or record doesn't exist
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// lookupHost returns a domain's addresses, or nil if the lookup fails or the resolver
+// isn't a HostResolver
+func (v *Validator) lookupHost(domain string) []string {
+	resolver, ok := v.resolver.(HostResolver)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.options.DNSTimeout)
+	defer cancel()
+
+	addrs, err := resolver.LookupHost(ctx, domain)
+	if err != nil {
+		return nil
+	}
+	return addrs
+}
+
+// privateAddr returns the first private (RFC 1918 or RFC 4193), loopback or link-local
+// address in addrs
+func privateAddr(addrs []string) (string, bool) {
+	for _, addr := range addrs {
+		ip, err := netip.ParseAddr(addr)
+		if err != nil {
+			continue
+		}
+		if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+			return addr, true
+		}
+	}
+	return "", false
+}
+
+// checkPrivateIP checks the addresses of a domain without MX records, which mail falls
+// back to (RFC 5321). A private address means the domain can't be a real mail host, so
+// it sets ResolvesToPrivateIP and returns ErrPrivateIPResolution when
+// RejectPrivateIPResolution is set.
+func (v *Validator) checkPrivateIP(result *ValidationResult, domain string, addrs []string) error {
+	if !v.options.RejectPrivateIPResolution {
+		return nil
+	}
+	addr, ok := privateAddr(addrs)
+	if !ok {
+		return nil
+	}
+
+	result.ResolvesToPrivateIP = true
+	return fmt.Errorf("%w: %s resolves to %s", ErrPrivateIPResolution, domain, addr)
+}
@@ -0,0 +1,283 @@
+package mailcop_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+// hostResolver is a staticResolver that also answers address lookups
+type hostResolver struct {
+	staticResolver
+	hosts map[string][]string
+}
+
+func (r hostResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	addrs, ok := r.hosts[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestCheckReceivable(t *testing.T) {
+	resolver := hostResolver{
+		staticResolver: staticResolver{
+			"company.org": {"mx1.company.org", "mx2.company.org"},
+			"null.org":    {"."},
+			"empty.org":   {},
+		},
+		hosts: map[string][]string{
+			"company.org":  {"192.0.2.1"},
+			"web-only.org": {"192.0.2.2"},
+		},
+	}
+
+	tests := []struct {
+		email              string
+		wantValid          bool
+		wantReceive        bool
+		wantNotReceivable  bool
+		wantValidUnchecked bool // Validity without CheckReceivable
+	}{
+		{email: "user@company.org", wantValid: true, wantReceive: true, wantValidUnchecked: true},
+		{email: "user@null.org", wantNotReceivable: true},
+		{email: "user@empty.org", wantNotReceivable: true, wantValidUnchecked: true},
+		{email: "user@web-only.org", wantNotReceivable: true},
+		{email: "user@missing.org"},
+	}
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.Resolver = resolver
+
+	unchecked, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	opts.CheckReceivable = true
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantValid, result.IsValid)
+			assert.Equal(t, tt.wantReceive, result.CanReceiveMail)
+			assert.Equal(t, tt.wantNotReceivable, errors.Is(result.LastError, mailcop.ErrNotReceivable))
+
+			result = unchecked.Validate(tt.email)
+			assert.Equal(t, tt.wantValidUnchecked, result.IsValid)
+			assert.Equal(t, tt.wantReceive, result.CanReceiveMail)
+		})
+	}
+
+	t.Run("null MX is rejected without CheckReceivable", func(t *testing.T) {
+		result := unchecked.Validate("user@null.org")
+		assert.ErrorIs(t, result.LastError, mailcop.ErrNullMX)
+		assert.ErrorIs(t, result.LastError, mailcop.ErrNotReceivable)
+	})
+
+	t.Run("without a HostResolver", func(t *testing.T) {
+		opts := opts
+		opts.Resolver = resolver.staticResolver
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("user@web-only.org")
+		assert.False(t, result.IsValid)
+		assert.NotErrorIs(t, result.LastError, mailcop.ErrNotReceivable)
+	})
+
+	t.Run("requires CheckDNS", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.CheckReceivable = true
+
+		_, err := mailcop.New(opts)
+		assert.Error(t, err)
+	})
+}
+
+func TestNullMX(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.Resolver = staticResolver{
+		"null.org":     {"."},
+		"company.org":  {"mx.company.org"},
+		"multiple.org": {".", "mx.multiple.org"}, // Not a null MX, which must be the only record
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		email     string
+		wantValid bool
+		wantNull  bool
+	}{
+		{email: "user@null.org", wantNull: true},
+		{email: "user@NULL.org", wantNull: true},
+		{email: "user@company.org", wantValid: true},
+		{email: "user@multiple.org", wantValid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantValid, result.IsValid)
+			assert.Equal(t, tt.wantNull, errors.Is(result.LastError, mailcop.ErrNullMX))
+			assert.Equal(t, !tt.wantNull, result.CanReceiveMail)
+			assert.True(t, result.HasMX)
+		})
+	}
+
+	t.Run("domain validation", func(t *testing.T) {
+		result := v.ValidateDomain("null.org")
+		assert.False(t, result.IsValid)
+		assert.ErrorIs(t, result.LastError, mailcop.ErrNullMX)
+	})
+}
+
+func TestMinMXRecords(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.MinMXRecords = 2
+	opts.Resolver = staticResolver{
+		"single.org":    {"mx.single.org."},
+		"redundant.org": {"mx1.redundant.org.", "mx2.redundant.org."},
+		"repeated.org":  {"mx.repeated.org.", "MX.repeated.org"},
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	result := v.Validate("user@redundant.org")
+	assert.True(t, result.IsValid)
+	assert.Equal(t, []string{"mx1.redundant.org", "mx2.redundant.org"}, result.MXRecords)
+
+	for _, email := range []string{"user@single.org", "user@repeated.org"} {
+		result := v.Validate(email)
+		assert.False(t, result.IsValid, email)
+		assert.Equal(t, mailcop.ReasonTooFewMX, result.Reason, email)
+		assert.ErrorIs(t, result.LastError, mailcop.ErrTooFewMX, email)
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		opts := opts
+		opts.MinMXRecords = 0
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("user@single.org")
+		assert.True(t, result.IsValid)
+		assert.Equal(t, []string{"mx.single.org"}, result.MXRecords)
+	})
+
+	t.Run("requires CheckDNS", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.MinMXRecords = 2
+
+		_, err := mailcop.New(opts)
+		assert.ErrorContains(t, err, "MinMXRecords requires CheckDNS")
+	})
+}
+
+func TestRejectPrivateIPResolution(t *testing.T) {
+	resolver := hostResolver{
+		staticResolver: staticResolver{
+			"company.org":  {"mx.company.org"},
+			"internal.org": {"mx.internal.org"}, // Only the implicit MX is checked
+			"empty.org":    {},
+		},
+		hosts: map[string][]string{
+			"internal.org": {"10.0.0.1"},
+			"empty.org":    {"192.168.1.10"},
+			"public.org":   {"192.0.2.1"},
+			"private.org":  {"192.0.2.1", "172.16.0.5"},
+			"loopback.org": {"127.0.0.1"},
+			"v6.org":       {"::1"},
+			"link.org":     {"169.254.1.1"},
+			"ula.org":      {"fd00::1"},
+		},
+	}
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.RejectPrivateIPResolution = true
+	opts.Resolver = resolver
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		email       string
+		wantPrivate bool
+		wantReason  mailcop.Reason
+	}{
+		{email: "user@company.org"},
+		{email: "user@internal.org"},
+		{email: "user@public.org", wantReason: mailcop.ReasonDNS},
+		{email: "user@missing.org", wantReason: mailcop.ReasonDNS},
+		{email: "user@empty.org", wantPrivate: true, wantReason: mailcop.ReasonPrivateIP},
+		{email: "user@private.org", wantPrivate: true, wantReason: mailcop.ReasonPrivateIP},
+		{email: "user@loopback.org", wantPrivate: true, wantReason: mailcop.ReasonPrivateIP},
+		{email: "user@v6.org", wantPrivate: true, wantReason: mailcop.ReasonPrivateIP},
+		{email: "user@link.org", wantPrivate: true, wantReason: mailcop.ReasonPrivateIP},
+		{email: "user@ula.org", wantPrivate: true, wantReason: mailcop.ReasonPrivateIP},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantReason == "", result.IsValid, result.LastError)
+			assert.Equal(t, tt.wantReason, result.Reason)
+			assert.Equal(t, tt.wantPrivate, result.ResolvesToPrivateIP)
+			if tt.wantPrivate {
+				assert.ErrorIs(t, result.LastError, mailcop.ErrPrivateIPResolution)
+			}
+		})
+	}
+
+	t.Run("takes precedence over CheckReceivable", func(t *testing.T) {
+		opts := opts
+		opts.CheckReceivable = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		assert.Equal(t, mailcop.ReasonPrivateIP, v.Validate("user@loopback.org").Reason)
+		assert.Equal(t, mailcop.ReasonNotReceivable, v.Validate("user@public.org").Reason)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		opts := opts
+		opts.RejectPrivateIPResolution = false
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("user@empty.org")
+		assert.True(t, result.IsValid)
+		assert.False(t, result.ResolvesToPrivateIP)
+	})
+
+	t.Run("requirements", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.RejectPrivateIPResolution = true
+
+		_, err := mailcop.New(opts)
+		assert.ErrorContains(t, err, "RejectPrivateIPResolution requires CheckDNS")
+
+		opts.CheckDNS = true
+		opts.Resolver = resolver.staticResolver
+		_, err = mailcop.New(opts)
+		assert.ErrorContains(t, err, "RejectPrivateIPResolution requires a HostResolver")
+	})
+}
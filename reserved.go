@@ -41,3 +41,9 @@ func (v *Validator) isReserved(domain string) bool {
 
 	return false
 }
+
+// isLocalhost checks if a domain is localhost or one of its subdomains
+func isLocalhost(domain string) bool {
+	domain = strings.ToLower(domain)
+	return domain == "localhost" || strings.HasSuffix(domain, ".localhost")
+}
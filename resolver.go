@@ -0,0 +1,129 @@
+package mailcop
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/miekg/dns"
+)
+
+// Resolver looks up MX records for a domain. *net.Resolver satisfies this interface,
+// and net.DefaultResolver is used when Options.Resolver is nil.
+type Resolver interface {
+	LookupMX(ctx context.Context, domain string) ([]*net.MX, error)
+}
+
+// HostResolver is a Resolver that can also look up the addresses of a host. With
+// Options.CheckReceivable, it's used to tell domains that resolve but have no MX records
+// apart from domains that don't exist. *net.Resolver satisfies this interface.
+type HostResolver interface {
+	Resolver
+	LookupHost(ctx context.Context, host string) (addrs []string, err error)
+}
+
+// ReverseResolver is a Resolver that can also look up the names of an address. It's
+// required by Options.RequireIPReverseDNS. *net.Resolver satisfies this interface.
+type ReverseResolver interface {
+	Resolver
+	LookupAddr(ctx context.Context, addr string) (names []string, err error)
+}
+
+// TXTResolver is a Resolver that can also look up TXT records. It's required by
+// Options.CheckSPFExists and Options.CheckDMARC. *net.Resolver satisfies this interface.
+type TXTResolver interface {
+	Resolver
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// DNSSECResolver is a Resolver that also reports whether an answer was DNSSEC-validated.
+// The standard library resolver can't provide this because it doesn't expose the AD
+// (Authenticated Data) bit of DNS responses, so a custom resolver such as DNSSECClient
+// is required for Options.RequireDNSSEC.
+type DNSSECResolver interface {
+	Resolver
+	LookupMXSecure(ctx context.Context, domain string) (mx []*net.MX, authenticated bool, err error)
+}
+
+// DNSSECClient is a DNSSECResolver that queries a single DNS server directly and
+// reports the AD bit of its responses. The server must be a validating resolver
+// (e.g. a local unbound instance, 1.1.1.1 or 8.8.8.8), and the path to it should be
+// trusted, since the AD bit is set by the server rather than verified locally.
+type DNSSECClient struct {
+	server string
+	client *dns.Client
+}
+
+// NewDNSSECClient creates a DNSSECClient for the given server address ("host" or
+// "host:port"). When server is empty, the first nameserver in /etc/resolv.conf is used.
+func NewDNSSECClient(server string) (*DNSSECClient, error) {
+	if server == "" {
+		config, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resolver config: %v", err)
+		}
+		if len(config.Servers) == 0 {
+			return nil, fmt.Errorf("no nameservers found in resolver config")
+		}
+		server = net.JoinHostPort(config.Servers[0], config.Port)
+	} else if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	return &DNSSECClient{
+		server: server,
+		client: new(dns.Client),
+	}, nil
+}
+
+// LookupMX returns the MX records for a domain
+func (c *DNSSECClient) LookupMX(ctx context.Context, domain string) ([]*net.MX, error) {
+	mx, _, err := c.LookupMXSecure(ctx, domain)
+	return mx, err
+}
+
+// LookupMXSecure returns the MX records for a domain and whether the server
+// marked the answer as DNSSEC-validated
+func (c *DNSSECClient) LookupMXSecure(ctx context.Context, domain string) ([]*net.MX, bool, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(domain), dns.TypeMX)
+	msg.SetEdns0(4096, true) // Set the DO bit to request DNSSEC processing
+	msg.AuthenticatedData = true
+
+	resp, _, err := c.client.ExchangeContext(ctx, msg, c.server)
+	if err == nil && resp.Truncated {
+		// Retry over TCP when the answer doesn't fit in a UDP response
+		tcp := &dns.Client{Net: "tcp", Timeout: c.client.Timeout}
+		resp, _, err = tcp.ExchangeContext(ctx, msg, c.server)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch resp.Rcode {
+	case dns.RcodeSuccess:
+	case dns.RcodeNameError:
+		return nil, false, &net.DNSError{Err: "no such host", Name: domain, Server: c.server, IsNotFound: true}
+	default:
+		return nil, false, &net.DNSError{Err: dns.RcodeToString[resp.Rcode], Name: domain, Server: c.server}
+	}
+
+	var records []*net.MX
+	for _, rr := range resp.Answer {
+		if mx, ok := rr.(*dns.MX); ok {
+			records = append(records, &net.MX{Host: mx.Mx, Pref: mx.Preference})
+		}
+	}
+
+	if len(records) == 0 {
+		return nil, resp.AuthenticatedData, &net.DNSError{
+			UnwrapErr:  errNoMXRecords,
+			Err:        "no such host",
+			Name:       domain,
+			Server:     c.server,
+			IsNotFound: true,
+		}
+	}
+
+	return records, resp.AuthenticatedData, nil
+}
@@ -0,0 +1,752 @@
+package mailcop_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+// startDNSServer runs a local DNS server that answers MX queries for secure.test
+// (with the AD bit set) and insecure.test (without it), an empty answer for nomx.test,
+// and NXDOMAIN otherwise.
+func startDNSServer(t *testing.T) string {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	mux := dns.NewServeMux()
+	mux.HandleFunc(".", func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+
+		name := r.Question[0].Name
+		switch name {
+		case "secure.test.", "insecure.test.":
+			m.AuthenticatedData = name == "secure.test."
+			m.Answer = append(m.Answer, &dns.MX{
+				Hdr:        dns.RR_Header{Name: name, Rrtype: dns.TypeMX, Class: dns.ClassINET, Ttl: 300},
+				Preference: 10,
+				Mx:         "mx." + name,
+			})
+		case "nomx.test.":
+		default:
+			m.Rcode = dns.RcodeNameError
+		}
+		_ = w.WriteMsg(m)
+	})
+
+	server := &dns.Server{PacketConn: pc, Handler: mux}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	t.Cleanup(func() {
+		_ = server.Shutdown()
+	})
+
+	return pc.LocalAddr().String()
+}
+
+// staticResolver is a Resolver that answers MX lookups from a fixed map of domains
+type staticResolver map[string][]string
+
+func (r staticResolver) LookupMX(_ context.Context, domain string) ([]*net.MX, error) {
+	hosts, ok := r[domain]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+	}
+
+	records := make([]*net.MX, 0, len(hosts))
+	for i, host := range hosts {
+		records = append(records, &net.MX{Host: host, Pref: uint16(10 * (i + 1))})
+	}
+	return records, nil
+}
+
+// errorResolver is a Resolver that fails MX lookups with a fixed error per domain
+// and answers the rest from staticResolver
+type errorResolver struct {
+	staticResolver
+	errors map[string]error
+}
+
+func (r errorResolver) LookupMX(ctx context.Context, domain string) ([]*net.MX, error) {
+	if err, ok := r.errors[domain]; ok {
+		return nil, err
+	}
+	return r.staticResolver.LookupMX(ctx, domain)
+}
+
+func TestDNSSECClient(t *testing.T) {
+	client, err := mailcop.NewDNSSECClient(startDNSServer(t))
+	require.NoError(t, err)
+
+	mx, authenticated, err := client.LookupMXSecure(context.Background(), "secure.test")
+	require.NoError(t, err)
+	assert.True(t, authenticated)
+	require.Len(t, mx, 1)
+	assert.Equal(t, "mx.secure.test.", mx[0].Host)
+	assert.Equal(t, uint16(10), mx[0].Pref)
+
+	_, authenticated, err = client.LookupMXSecure(context.Background(), "insecure.test")
+	require.NoError(t, err)
+	assert.False(t, authenticated)
+
+	_, err = client.LookupMX(context.Background(), "missing.test")
+	var dnsErr *net.DNSError
+	require.ErrorAs(t, err, &dnsErr)
+	assert.True(t, dnsErr.IsNotFound)
+}
+
+func TestRequireDNSSEC(t *testing.T) {
+	client, err := mailcop.NewDNSSECClient(startDNSServer(t))
+	require.NoError(t, err)
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.Resolver = client
+
+	t.Run("flag only", func(t *testing.T) {
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("user@secure.test")
+		assert.True(t, result.IsValid)
+		assert.True(t, result.IsDNSSECValidated)
+
+		result = v.Validate("user@insecure.test")
+		assert.True(t, result.IsValid)
+		assert.False(t, result.IsDNSSECValidated)
+
+		assert.False(t, v.Validate("user@missing.test").IsValid)
+	})
+
+	t.Run("required", func(t *testing.T) {
+		opts := opts
+		opts.RequireDNSSEC = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		assert.True(t, v.Validate("user@secure.test").IsValid)
+
+		result := v.Validate("user@insecure.test")
+		assert.False(t, result.IsValid)
+		assert.Error(t, result.LastError)
+	})
+
+	t.Run("requires a DNSSEC resolver", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.CheckDNS = true
+		opts.RequireDNSSEC = true
+
+		_, err := mailcop.New(opts)
+		assert.Error(t, err)
+
+		opts.Resolver = client
+		opts.CheckDNS = false
+		_, err = mailcop.New(opts)
+		assert.Error(t, err)
+	})
+}
+
+// slowResolver is a Resolver that waits before answering every MX lookup
+type slowResolver struct {
+	delay time.Duration
+}
+
+func (r slowResolver) LookupMX(ctx context.Context, domain string) ([]*net.MX, error) {
+	select {
+	case <-time.After(r.delay):
+		return []*net.MX{{Host: "mx." + domain + ".", Pref: 10}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestDNSFailOpen(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.Resolver = errorResolver{
+		staticResolver: staticResolver{"company.org": {"mx.company.org"}},
+		errors: map[string]error{
+			"timeout.org":  &net.DNSError{Err: "i/o timeout", Name: "timeout.org", IsTimeout: true},
+			"servfail.org": &net.DNSError{Err: "server misbehaving", Name: "servfail.org", IsTemporary: true},
+		},
+	}
+
+	closed, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	opts.DNSFailOpen = true
+	open, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		email            string
+		wantOpen         bool
+		wantClosed       bool
+		wantInconclusive bool
+	}{
+		{email: "user@company.org", wantOpen: true, wantClosed: true},
+		{email: "user@timeout.org", wantOpen: true, wantInconclusive: true},
+		{email: "user@servfail.org", wantOpen: true, wantInconclusive: true},
+		{email: "user@missing.org"}, // NXDOMAIN is definitive
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := open.Validate(tt.email)
+			assert.Equal(t, tt.wantOpen, result.IsValid)
+			assert.Equal(t, tt.wantInconclusive, result.DNSInconclusive)
+			if tt.wantInconclusive {
+				assert.False(t, result.HasMX)
+				assert.NoError(t, result.LastError)
+			}
+
+			result = closed.Validate(tt.email)
+			assert.Equal(t, tt.wantClosed, result.IsValid)
+			assert.False(t, result.DNSInconclusive)
+		})
+	}
+
+	t.Run("skips MX-dependent checks", func(t *testing.T) {
+		opts := opts
+		opts.CheckReceivable = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.Validate("user@timeout.org")
+		assert.True(t, result.IsValid)
+		assert.True(t, result.DNSInconclusive)
+		assert.False(t, result.CanReceiveMail)
+	})
+}
+
+func TestDNSStatus(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.Resolver = errorResolver{
+		staticResolver: staticResolver{
+			"company.org": {"mx.company.org"},
+			"nomx.org":    {},
+			"null.org":    {"."},
+		},
+		errors: map[string]error{
+			"timeout.org":  &net.DNSError{Err: "i/o timeout", Name: "timeout.org", IsTimeout: true},
+			"deadline.org": context.DeadlineExceeded,
+			"servfail.org": &net.DNSError{Err: "server misbehaving", Name: "servfail.org", IsTemporary: true},
+			"refused.org":  &net.DNSError{Err: "REFUSED", Name: "refused.org"},
+		},
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		domain string
+		want   mailcop.DNSStatus
+	}{
+		{domain: "company.org", want: mailcop.DNSStatusOK},
+		{domain: "missing.org", want: mailcop.DNSStatusNXDomain},
+		{domain: "nomx.org", want: mailcop.DNSStatusNoMX},
+		{domain: "null.org", want: mailcop.DNSStatusNullMX},
+		{domain: "timeout.org", want: mailcop.DNSStatusTimeout},
+		{domain: "deadline.org", want: mailcop.DNSStatusTimeout},
+		{domain: "servfail.org", want: mailcop.DNSStatusServerError},
+		{domain: "refused.org", want: mailcop.DNSStatusServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			result := v.Validate("user@" + tt.domain)
+			assert.Equal(t, tt.want, result.DNSStatus, result.DNSStatus.String())
+		})
+	}
+
+	t.Run("lookup timeout", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.CheckDNS = true
+		opts.DNSTimeout = 10 * time.Millisecond
+		opts.Resolver = slowResolver{delay: time.Second}
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+		assert.Equal(t, mailcop.DNSStatusTimeout, v.Validate("user@slow.org").DNSStatus)
+	})
+
+	t.Run("not checked", func(t *testing.T) {
+		v, err := mailcop.New(mailcop.DefaultOptions())
+		require.NoError(t, err)
+
+		result := v.Validate("user@company.org")
+		assert.Equal(t, mailcop.DNSStatusNotChecked, result.DNSStatus)
+		assert.Equal(t, "not_checked", result.DNSStatus.String())
+	})
+
+	t.Run("DNSSECClient tells no MX from NXDOMAIN", func(t *testing.T) {
+		client, err := mailcop.NewDNSSECClient(startDNSServer(t))
+		require.NoError(t, err)
+
+		opts := mailcop.DefaultOptions()
+		opts.CheckDNS = true
+		opts.Resolver = client
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+		assert.Equal(t, mailcop.DNSStatusNoMX, v.Validate("user@nomx.test").DNSStatus)
+		assert.Equal(t, mailcop.DNSStatusNXDomain, v.Validate("user@missing.test").DNSStatus)
+	})
+}
+
+// reverseResolver is a staticResolver that also answers PTR lookups
+type reverseResolver struct {
+	staticResolver
+	names map[string][]string
+}
+
+func (r reverseResolver) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	names, ok := r.names[addr]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+	}
+	return names, nil
+}
+
+func TestRequireIPReverseDNS(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.RequireIPReverseDNS = true
+	opts.Resolver = reverseResolver{
+		names: map[string][]string{
+			"192.0.2.1":   {"mail.company.org."},
+			"2001:db8::1": {"mail6.company.org."},
+			"192.0.2.3":   {},
+		},
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		email       string
+		wantValid   bool
+		wantReverse string
+	}{
+		{email: "user@[192.0.2.1]", wantValid: true, wantReverse: "mail.company.org"},
+		{email: "user@[IPv6:2001:db8::1]", wantValid: true, wantReverse: "mail6.company.org"},
+		{email: "user@[192.0.2.2]"},
+		{email: "user@[192.0.2.3]"},
+		{email: "user@company.org", wantValid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantValid, result.IsValid)
+			assert.Equal(t, tt.wantReverse, result.IPReverseDNS)
+			if !tt.wantValid {
+				assert.ErrorIs(t, result.LastError, mailcop.ErrNoReverseDNS)
+				assert.Equal(t, mailcop.ReasonNoReverseDNS, result.Reason)
+			}
+		})
+	}
+
+	t.Run("requires a ReverseResolver", func(t *testing.T) {
+		opts := opts
+		opts.Resolver = staticResolver{}
+
+		_, err := mailcop.New(opts)
+		assert.Error(t, err)
+	})
+}
+
+// blockingResolver counts MX lookups and holds them until release is closed
+type blockingResolver struct {
+	calls   *atomic.Int32
+	release chan struct{}
+}
+
+func (r blockingResolver) LookupMX(ctx context.Context, domain string) ([]*net.MX, error) {
+	r.calls.Add(1)
+	select {
+	case <-r.release:
+		return []*net.MX{{Host: "mx." + domain, Pref: 10}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestConcurrentLookupsShareQuery(t *testing.T) {
+	resolver := blockingResolver{calls: &atomic.Int32{}, release: make(chan struct{})}
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.Resolver = resolver
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]mailcop.ValidationResult, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = v.Validate(fmt.Sprintf("user%d@company.org", i))
+		}()
+	}
+
+	// Give every caller time to join the in-flight lookup
+	time.Sleep(50 * time.Millisecond)
+	close(resolver.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), resolver.calls.Load())
+	for _, result := range results {
+		assert.True(t, result.IsValid)
+		assert.True(t, result.HasMX)
+	}
+
+	t.Run("canceled caller doesn't fail the lookup", func(t *testing.T) {
+		resolver := blockingResolver{calls: &atomic.Int32{}, release: make(chan struct{})}
+		opts.Resolver = resolver
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		result := v.ValidateWithTimeout("user@company.org", 10*time.Millisecond)
+		assert.False(t, result.IsValid)
+
+		close(resolver.release)
+		assert.Eventually(t, func() bool {
+			return v.Validate("user@company.org").IsValid
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, int32(1), resolver.calls.Load())
+	})
+}
+
+// txtResolver is a staticResolver that also answers TXT lookups and counts them
+type txtResolver struct {
+	staticResolver
+	txt   map[string][]string
+	calls *atomic.Int32
+}
+
+func (r txtResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	r.calls.Add(1)
+	records, ok := r.txt[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func TestCheckSPFExists(t *testing.T) {
+	resolver := txtResolver{
+		staticResolver: staticResolver{
+			"company.org": {"mx.company.org"},
+			"nospf.org":   {"mx.nospf.org"},
+			"other.org":   {"mx.other.org"},
+			"spf10.org":   {"mx.spf10.org"},
+		},
+		txt: map[string][]string{
+			"company.org": {"google-site-verification=abc", "V=SPF1 include:_spf.example.com ~all"},
+			"other.org":   {"v=spf1"},
+			"spf10.org":   {"v=spf10 -all"},
+			"nospf.org":   {"some other record"},
+		},
+		calls: &atomic.Int32{},
+	}
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.CheckSPFExists = true
+	opts.Resolver = resolver
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		email   string
+		wantSPF bool
+	}{
+		{email: "user@company.org", wantSPF: true},
+		{email: "user@other.org", wantSPF: true},
+		{email: "user@spf10.org"},
+		{email: "user@nospf.org"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.True(t, result.IsValid, "SPF is informational")
+			assert.Equal(t, tt.wantSPF, result.HasSPF)
+		})
+	}
+
+	t.Run("TXT results are cached", func(t *testing.T) {
+		calls := resolver.calls.Load()
+		assert.True(t, v.Validate("other@company.org").HasSPF)
+		assert.Equal(t, calls, resolver.calls.Load())
+	})
+
+	t.Run("requires a TXTResolver", func(t *testing.T) {
+		opts := opts
+		opts.Resolver = staticResolver{}
+
+		_, err := mailcop.New(opts)
+		assert.Error(t, err)
+	})
+
+	t.Run("requires CheckDNS", func(t *testing.T) {
+		opts := opts
+		opts.CheckDNS = false
+
+		_, err := mailcop.New(opts)
+		assert.Error(t, err)
+	})
+}
+
+func TestCheckDMARC(t *testing.T) {
+	resolver := txtResolver{
+		staticResolver: staticResolver{
+			"company.org": {"mx.company.org"},
+			"nodmarc.org": {"mx.nodmarc.org"},
+		},
+		txt: map[string][]string{
+			"company.org":        {"v=spf1 -all"},
+			"_dmarc.company.org": {"v=DMARC1; p=reject; rua=mailto:dmarc@company.org"},
+			"nodmarc.org":        {"v=DMARC1; p=none"}, // Only counts at _dmarc.
+		},
+		calls: &atomic.Int32{},
+	}
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.CheckDMARC = true
+	opts.Resolver = resolver
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	result := v.Validate("user@company.org")
+	assert.True(t, result.IsValid)
+	assert.True(t, result.HasDMARC)
+	assert.False(t, result.HasSPF, "SPF isn't checked without CheckSPFExists")
+
+	result = v.Validate("user@nodmarc.org")
+	assert.True(t, result.IsValid, "DMARC is informational by default")
+	assert.False(t, result.HasDMARC)
+
+	t.Run("RejectNoDMARC", func(t *testing.T) {
+		opts := opts
+		opts.RejectNoDMARC = true
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+
+		assert.True(t, v.Validate("user@company.org").IsValid)
+
+		result := v.Validate("user@nodmarc.org")
+		assert.False(t, result.IsValid)
+		assert.ErrorIs(t, result.LastError, mailcop.ErrNoDMARC)
+		assert.Equal(t, mailcop.ReasonNoDMARC, result.Reason)
+		assert.Contains(t, v.Explain("user@nodmarc.org"), "DMARC: failed: domain has no DMARC record: nodmarc.org")
+	})
+
+	t.Run("concurrent TTL changes", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				v.Validate("user@company.org")
+			}()
+			go func() {
+				defer wg.Done()
+				v.SetDNSCacheTTL(time.Duration(i+1) * time.Minute)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("requires a TXTResolver", func(t *testing.T) {
+		opts := opts
+		opts.Resolver = staticResolver{}
+
+		_, err := mailcop.New(opts)
+		assert.Error(t, err)
+	})
+}
+
+func TestRequiredMXSuffixes(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.RequiredMXSuffixes = []string{".google.com", "outlook.com.", ".corp-mail.net"}
+	opts.Resolver = staticResolver{
+		"workspace.org":  {"aspmx.l.google.com."},
+		"office.org":     {"office-org.mail.protection.OUTLOOK.com."},
+		"backup.org":     {"mx.backup.org.", "alt1.aspmx.l.google.com."},
+		"hosted.org":     {"mx1.corp-mail.net."},
+		"zoho.org":       {"mx.zoho.eu."},
+		"selfhosted.org": {"mx.selfhosted.org."},
+		"lookalike.org":  {"mx.notgoogle.com."},
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"user@workspace.org": "google",
+		"user@office.org":    "microsoft",
+		"user@backup.org":    "google",
+		"user@hosted.org":    "corp-mail.net", // No provider pattern, so the matching suffix
+	}
+	for email, provider := range tests {
+		result := v.Validate(email)
+		assert.True(t, result.IsValid, email)
+		assert.Equal(t, provider, result.MailProvider, email)
+	}
+
+	for _, email := range []string{"user@selfhosted.org", "user@lookalike.org"} {
+		result := v.Validate(email)
+		assert.False(t, result.IsValid, email)
+		assert.Equal(t, mailcop.ReasonMailProvider, result.Reason, email)
+		assert.ErrorIs(t, result.LastError, mailcop.ErrMailProviderNotAllowed, email)
+		assert.Empty(t, result.MailProvider, email)
+	}
+
+	// The provider is still reported when it isn't one of the required ones
+	result := v.Validate("user@zoho.org")
+	assert.Equal(t, mailcop.ReasonMailProvider, result.Reason)
+	assert.Equal(t, "zoho", result.MailProvider)
+
+	t.Run("requires CheckDNS", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.RequiredMXSuffixes = []string{".google.com"}
+
+		_, err := mailcop.New(opts)
+		assert.ErrorContains(t, err, "RequiredMXSuffixes requires CheckDNS")
+	})
+}
+
+func TestMailProviderDetection(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.Resolver = staticResolver{
+		"workspace.org":  {"ASPMX.L.GOOGLE.COM."},
+		"proton.org":     {"mail.protonmail.ch.", "mailsec.protonmail.ch."},
+		"icloud.org":     {"mx01.mail.icloud.com."},
+		"corp.org":       {"inbound.mx.corp.example."},
+		"selfhosted.org": {"mx.selfhosted.org."},
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"user@workspace.org":  "google",
+		"user@proton.org":     "proton",
+		"user@icloud.org":     "apple",
+		"user@corp.org":       "",
+		"user@selfhosted.org": "",
+	}
+	for email, provider := range tests {
+		result := v.Validate(email)
+		assert.True(t, result.IsValid, email)
+		assert.Equal(t, provider, result.MailProvider, email)
+	}
+
+	v.RegisterMXProviderPatterns(map[string][]string{
+		"corp":   {"*.mx.corp.example"},
+		"google": {"selfhosted.org"},
+	})
+	assert.Equal(t, "corp", v.Validate("user@corp.org").MailProvider)
+	assert.Equal(t, "google", v.Validate("user@selfhosted.org").MailProvider)
+
+	t.Run("not detected without DNS", func(t *testing.T) {
+		assert.Empty(t, v.ValidateWith("user@workspace.org", mailcop.WithDNS(false)).MailProvider)
+	})
+}
+
+// countingResolver is a staticResolver that counts its MX lookups
+type countingResolver struct {
+	staticResolver
+	lookups *atomic.Int64
+}
+
+func (r countingResolver) LookupMX(ctx context.Context, domain string) ([]*net.MX, error) {
+	r.lookups.Add(1)
+	return r.staticResolver.LookupMX(ctx, domain)
+}
+
+func TestWouldCheckDNS(t *testing.T) {
+	var lookups atomic.Int64
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.CheckDisposable = true
+	opts.RejectDisposable = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+	opts.DNSCacheTTL = time.Hour
+	opts.Resolver = countingResolver{
+		staticResolver: staticResolver{"company.org": {"mx.company.org."}, "other.org": {"mx.other.org."}},
+		lookups:        &lookups,
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	v.Validate("user@other.org")
+	lookups.Store(0)
+
+	tests := []struct {
+		email string
+		want  bool
+	}{
+		{"user@company.org", true},
+		{"Jane <jane@Company.org>", true},
+		{"user@other.org", false},    // Cached
+		{"not an email", false},      // Rejected by parsing
+		{"user@tempmail.com", false}, // Rejected as disposable before the MX lookup
+		{"user@[192.0.2.1]", false},  // IP domain
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			assert.Equal(t, tt.want, v.WouldCheckDNS(tt.email))
+		})
+	}
+	assert.Zero(t, lookups.Load(), "WouldCheckDNS doesn't look anything up")
+	assert.Equal(t, int64(1), v.Stats().Validated)
+
+	t.Run("after validating", func(t *testing.T) {
+		v.Validate("user@company.org")
+		assert.False(t, v.WouldCheckDNS("user@company.org"))
+	})
+
+	t.Run("MX checked first", func(t *testing.T) {
+		opts := opts
+		opts.CheckOrder = []mailcop.CheckType{mailcop.CheckTypeMX, mailcop.CheckTypeDisposable}
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+		assert.True(t, v.WouldCheckDNS("user@tempmail.com"))
+	})
+
+	t.Run("DNS disabled", func(t *testing.T) {
+		opts := opts
+		opts.CheckDNS = false
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+		assert.False(t, v.WouldCheckDNS("user@company.org"))
+	})
+}
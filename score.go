@@ -0,0 +1,72 @@
+package mailcop
+
+// ScoreWeights configures how much each signal contributes to ValidationResult.Score.
+// Only signals that were evaluated count towards the score, so disabling a check
+// (e.g. CheckDNS) removes its weight from the calculation entirely. The score is the
+// sum of the weights of the satisfied signals divided by the sum of the weights of
+// all evaluated signals, which keeps it in the range 0 to 1 for any set of weights.
+type ScoreWeights struct {
+	Format          float64 // Address parsed successfully
+	MX              float64 // Domain passed the MX lookup (evaluated when CheckDNS is enabled)
+	NotDisposable   float64 // Domain is not disposable (evaluated when CheckDisposable is enabled)
+	NotFreeProvider float64 // Domain is not a free provider (evaluated when CheckFreeProvider is enabled)
+	NotIPDomain     float64 // Domain is not an IP address
+	NotReserved     float64 // Domain is not a reserved example domain
+}
+
+// DefaultScoreWeights returns the default score weights. Format and deliverability
+// signals dominate, while a free provider only lowers the score slightly:
+//
+//	Format:          0.35
+//	MX:              0.25
+//	NotDisposable:   0.20
+//	NotReserved:     0.10
+//	NotIPDomain:     0.05
+//	NotFreeProvider: 0.05
+func DefaultScoreWeights() ScoreWeights {
+	return ScoreWeights{
+		Format:          0.35,
+		MX:              0.25,
+		NotDisposable:   0.20,
+		NotFreeProvider: 0.05,
+		NotIPDomain:     0.05,
+		NotReserved:     0.10,
+	}
+}
+
+// score computes a deterministic confidence score for a validation result.
+// An address or domain that fails to parse always scores 0.
+func (v *Validator) score(result ValidationResult) float64 {
+	if result.Address == "" && result.Domain == "" || result.Reason == ReasonSyntax {
+		return 0
+	}
+
+	w := v.options.ScoreWeights
+
+	var total, earned float64
+	add := func(weight float64, satisfied bool) {
+		total += weight
+		if satisfied {
+			earned += weight
+		}
+	}
+
+	add(w.Format, true)
+	add(w.NotIPDomain, !result.IsIPDomain)
+	add(w.NotReserved, !result.IsReserved)
+
+	if v.options.CheckDisposable {
+		add(w.NotDisposable, !result.IsDisposable)
+	}
+	if v.options.CheckFreeProvider {
+		add(w.NotFreeProvider, !result.IsFreeProvider)
+	}
+	if v.checkDNS() {
+		add(w.MX, result.HasMX)
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return earned / total
+}
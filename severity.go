@@ -0,0 +1,95 @@
+package mailcop
+
+import "fmt"
+
+// Severity is what happens when a check flags an email: nothing, a warning in
+// ValidationResult.Warnings that keeps the email valid, or a rejection. It's set per
+// check with Options.Severities.
+type Severity int
+
+const (
+	SeverityDefault Severity = iota // Use the check's Reject* option: SeverityReject when it's set, SeverityOff otherwise
+	SeverityOff                     // Set the flag (e.g. IsDisposable) only
+	SeverityWarn                    // Set the flag and add a warning, keeping the email valid
+	SeverityReject                  // Set the flag and reject the email
+)
+
+// severityChecks are the checks whose severity can be set: the ones with a Reject*
+// option (RejectIPDomains, RejectReserved, RejectDisposable and RejectFreeProvider)
+var severityChecks = map[CheckType]bool{
+	CheckTypeIPDomain:     true,
+	CheckTypeReserved:     true,
+	CheckTypeDisposable:   true,
+	CheckTypeFreeProvider: true,
+}
+
+// String returns the severity as a short lowercase name like "warn"
+func (s Severity) String() string {
+	switch s {
+	case SeverityDefault:
+		return "default"
+	case SeverityOff:
+		return "off"
+	case SeverityWarn:
+		return "warn"
+	case SeverityReject:
+		return "reject"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// MarshalText encodes the severity as its String name, so Severities reads naturally in JSON
+func (s Severity) MarshalText() ([]byte, error) {
+	if s < SeverityDefault || s > SeverityReject {
+		return nil, fmt.Errorf("unknown severity: %d", int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name like "warn"
+func (s *Severity) UnmarshalText(text []byte) error {
+	for severity := SeverityDefault; severity <= SeverityReject; severity++ {
+		if severity.String() == string(text) {
+			*s = severity
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity: %q", text)
+}
+
+// validateSeverities reports unknown severities and checks without a severity
+func validateSeverities(severities map[CheckType]Severity) error {
+	for check, severity := range severities {
+		if !severityChecks[check] {
+			return fmt.Errorf("severity can't be set for check: %v", check)
+		}
+		if severity < SeverityDefault || severity > SeverityReject {
+			return fmt.Errorf("unknown severity for %v: %v", check, severity)
+		}
+	}
+	return nil
+}
+
+// severity returns the severity of a check, falling back to its Reject* option
+func (v *Validator) severity(check CheckType, reject bool) Severity {
+	if severity := v.options.Severities[check]; severity != SeverityDefault {
+		return severity
+	}
+	if reject {
+		return SeverityReject
+	}
+	return SeverityOff
+}
+
+// flag applies the severity of a check that flagged a result: rejecting it with err
+// and reason, or adding err to its Warnings. It reports whether validation should stop.
+func (v *Validator) flag(result *ValidationResult, check CheckType, reject bool, err error, reason Reason) bool {
+	switch v.severity(check, reject) {
+	case SeverityReject:
+		return v.fail(result, err, reason)
+	case SeverityWarn:
+		result.Warnings = append(result.Warnings, err.Error())
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package mailcop_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestSeverities(t *testing.T) {
+	newValidator := func(t *testing.T, severities map[mailcop.CheckType]mailcop.Severity) *mailcop.Validator {
+		opts := mailcop.DefaultOptions()
+		opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+		opts.CheckDisposable = true
+		opts.CheckFreeProvider = true
+		opts.RejectDisposable = true
+		opts.Severities = severities
+
+		v, err := mailcop.New(opts)
+		require.NoError(t, err)
+		return v
+	}
+
+	tests := []struct {
+		name         string
+		severities   map[mailcop.CheckType]mailcop.Severity
+		email        string
+		wantValid    bool
+		wantReason   mailcop.Reason
+		wantWarnings []string
+	}{
+		{
+			name:       "Reject option by default",
+			email:      "user@tempmail.com",
+			wantReason: mailcop.ReasonDisposable,
+		},
+		{
+			name:         "warn overrides the Reject option",
+			severities:   map[mailcop.CheckType]mailcop.Severity{mailcop.CheckTypeDisposable: mailcop.SeverityWarn},
+			email:        "user@tempmail.com",
+			wantValid:    true,
+			wantWarnings: []string{"disposable domain: tempmail.com"},
+		},
+		{
+			name:       "off overrides the Reject option",
+			severities: map[mailcop.CheckType]mailcop.Severity{mailcop.CheckTypeDisposable: mailcop.SeverityOff},
+			email:      "user@tempmail.com",
+			wantValid:  true,
+		},
+		{
+			name:       "reject without the Reject option",
+			severities: map[mailcop.CheckType]mailcop.Severity{mailcop.CheckTypeFreeProvider: mailcop.SeverityReject},
+			email:      "user@gmail.com",
+			wantReason: mailcop.ReasonFreeProvider,
+		},
+		{
+			name:         "warn on a reserved domain",
+			severities:   map[mailcop.CheckType]mailcop.Severity{mailcop.CheckTypeReserved: mailcop.SeverityWarn},
+			email:        "user@example.com",
+			wantValid:    true,
+			wantWarnings: []string{"reserved domain: example.com"},
+		},
+		{
+			name:         "warn on a free provider",
+			severities:   map[mailcop.CheckType]mailcop.Severity{mailcop.CheckTypeFreeProvider: mailcop.SeverityWarn},
+			email:        "user@gmail.com",
+			wantValid:    true,
+			wantWarnings: []string{"free email provider: gmail.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newValidator(t, tt.severities).Validate(tt.email)
+			assert.Equal(t, tt.wantValid, result.IsValid, result.LastError)
+			assert.Equal(t, tt.wantReason, result.Reason)
+			assert.Equal(t, tt.wantWarnings, result.Warnings)
+		})
+	}
+
+	t.Run("flags are set either way", func(t *testing.T) {
+		v := newValidator(t, map[mailcop.CheckType]mailcop.Severity{mailcop.CheckTypeDisposable: mailcop.SeverityWarn})
+		result := v.Validate("user@tempmail.com")
+		assert.True(t, result.IsDisposable)
+	})
+
+	t.Run("per call", func(t *testing.T) {
+		v := newValidator(t, nil)
+		result := v.ValidateWith("user@tempmail.com", mailcop.WithSeverity(mailcop.CheckTypeDisposable, mailcop.SeverityWarn))
+		assert.True(t, result.IsValid)
+		assert.Len(t, result.Warnings, 1)
+
+		assert.False(t, v.Validate("user@tempmail.com").IsValid, "the validator's own severities are unchanged")
+	})
+
+	t.Run("explained", func(t *testing.T) {
+		v := newValidator(t, map[mailcop.CheckType]mailcop.Severity{mailcop.CheckTypeDisposable: mailcop.SeverityWarn})
+		assert.Contains(t, v.Explain("user@tempmail.com"), "Warning: disposable domain: tempmail.com\n")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		opts := mailcop.DefaultOptions()
+		opts.Severities = map[mailcop.CheckType]mailcop.Severity{mailcop.CheckTypeMX: mailcop.SeverityWarn}
+		_, err := mailcop.New(opts)
+		assert.ErrorContains(t, err, "severity can't be set for check: mx")
+
+		opts.Severities = map[mailcop.CheckType]mailcop.Severity{mailcop.CheckTypeDisposable: 7}
+		_, err = mailcop.New(opts)
+		assert.ErrorContains(t, err, "unknown severity")
+	})
+}
+
+func TestSeverityJSON(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.Severities = map[mailcop.CheckType]mailcop.Severity{
+		mailcop.CheckTypeDisposable:   mailcop.SeverityWarn,
+		mailcop.CheckTypeFreeProvider: mailcop.SeverityReject,
+	}
+
+	data, err := json.Marshal(opts)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Severities":{"disposable":"warn","free_provider":"reject"}`)
+
+	var decoded mailcop.Options
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, opts.Severities, decoded.Severities)
+
+	err = json.Unmarshal([]byte(`{"Severities":{"disposable":"loud"}}`), &decoded)
+	assert.Error(t, err)
+}
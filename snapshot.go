@@ -0,0 +1,239 @@
+package mailcop
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bits-and-blooms/bloom/v3"
+)
+
+// snapshotVersion is the version of the format written by Snapshot
+const snapshotVersion = 1
+
+// snapshot is the serialized state of a Validator
+type snapshot struct {
+	Version              int
+	DisposableDomains    map[string]string    // Domain to source (map-based validation only)
+	DisposableAlsoIn     map[string][]string  // Further sources of domains listed by more than one source
+	DisposableMX         []string             // Disposable MX hosts
+	DisposableUpdated    time.Time            // When the disposable list was last loaded
+	BloomFilters         []*bloom.BloomFilter // Primary filter followed by the salted filters
+	BloomOptions         BloomOptions         // Bloom filter options
+	FreeProviders        []string             // Free email providers
+	FreeProvidersUpdated time.Time            // When the free providers list was last loaded
+	TrustedDomains       []string             // Trusted domains
+	ProtectedDomains     map[string]string    // Protected domains keyed by their confusable skeleton
+	AllowedDomains       []string             // Compiled AllowedDomainPatterns
+	SpamtrapAddresses    []string             // Compiled spamtrap patterns for full addresses
+	SpamtrapLocalParts   []string             // Compiled spamtrap patterns for local parts
+	BannedLocalParts     []string             // Banned local parts
+	ExistingAddresses    map[string]string    // Registered addresses keyed by their canonical form
+	TLDs                 []string             // Known top-level domains
+	MXProviders          map[string]string    // Mail provider names keyed by MX host suffix
+	DNSCache             []snapshotDNSEntry   // Successful MX lookups, least recently used first
+}
+
+// snapshotDNSEntry is a cached MX lookup in a snapshot
+type snapshotDNSEntry struct {
+	Domain        string
+	MX            []*net.MX
+	Authenticated bool
+	CachedAt      time.Time
+}
+
+// dnsCacheKeys is implemented by DNS caches that can list their domains, like the
+// default cache and hashicorp/golang-lru/v2
+type dnsCacheKeys interface {
+	Keys() []string
+}
+
+// Snapshot writes the validator's in-memory state to w: the disposable domains (map or
+// bloom filter), disposable MX hosts, free providers, trusted and protected domains,
+// allowed domain and spamtrap patterns, banned local parts, existing addresses, TLDs,
+// mail provider patterns and successful DNS cache entries. Use Restore to load it into another validator,
+// e.g. to warm-start a fleet from one precomputed artifact. Failed lookups aren't
+// saved, and the DNS cache is only saved when it can list its domains with a
+// Keys() []string method.
+func (v *Validator) Snapshot(w io.Writer) error {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	s := snapshot{
+		Version:              snapshotVersion,
+		DisposableDomains:    make(map[string]string, len(v.disposableDomains)),
+		DisposableAlsoIn:     v.disposableAlsoIn,
+		DisposableMX:         sortedKeys(v.disposableMX),
+		DisposableUpdated:    v.disposableUpdated,
+		BloomOptions:         v.bloomOptions,
+		FreeProviders:        sortedKeys(v.freeProviders),
+		FreeProvidersUpdated: v.freeProvidersUpdated,
+		TrustedDomains:       sortedKeys(v.trustedDomains),
+		ProtectedDomains:     v.protectedDomains,
+		AllowedDomains:       patternStrings(v.allowedDomains),
+		SpamtrapAddresses:    patternStrings(v.spamtrapAddresses),
+		SpamtrapLocalParts:   patternStrings(v.spamtrapLocalParts),
+		BannedLocalParts:     sortedKeys(v.bannedLocalParts),
+		ExistingAddresses:    v.existingAddresses,
+		TLDs:                 sortedKeys(v.tlds),
+		MXProviders:          v.mxProviders,
+	}
+	for domain := range v.disposableDomains {
+		s.DisposableDomains[domain] = v.disposableSources[domain]
+	}
+	if v.bloomFilter != nil {
+		s.BloomFilters = append([]*bloom.BloomFilter{v.bloomFilter}, v.bloomSalted...)
+	}
+
+	if cache, ok := v.dnsCache.(dnsCacheKeys); ok {
+		for _, domain := range cache.Keys() {
+			// Lookups made through ValidateWithResolver are keyed by resolver name and not saved
+			entry, ok := v.dnsCache.Get(domain)
+			if !ok || entry.Err != nil || strings.Contains(domain, "|") {
+				continue
+			}
+			s.DNSCache = append(s.DNSCache, snapshotDNSEntry{
+				Domain:        domain,
+				MX:            entry.MX,
+				Authenticated: entry.Authenticated,
+				CachedAt:      entry.CachedAt,
+			})
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(s); err != nil {
+		return fmt.Errorf("failed to write snapshot: %v", err)
+	}
+	return nil
+}
+
+// Restore replaces the validator's in-memory state with a snapshot written by Snapshot.
+// DNS cache entries are added to the current cache and keep their original CachedAt, so
+// they expire as they would have on the validator that wrote the snapshot. Options,
+// including CheckDisposable and the other flags, aren't part of the snapshot.
+func (v *Validator) Restore(r io.Reader) error {
+	var s snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return fmt.Errorf("failed to read snapshot: %v", err)
+	}
+	if s.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", s.Version)
+	}
+
+	allowed, err := compileSnapshotPatterns(s.AllowedDomains)
+	if err != nil {
+		return err
+	}
+	spamtrapAddresses, err := compileSnapshotPatterns(s.SpamtrapAddresses)
+	if err != nil {
+		return err
+	}
+	spamtrapLocalParts, err := compileSnapshotPatterns(s.SpamtrapLocalParts)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	defer v.invalidateVerdicts()
+
+	v.disposableDomains = make(map[string]struct{}, len(s.DisposableDomains))
+	v.disposableSources = make(map[string]string, len(s.DisposableDomains))
+	v.disposableAlsoIn = make(map[string][]string, len(s.DisposableAlsoIn))
+	if v.disposableTrie != nil {
+		v.disposableTrie = newSuffixTrie()
+	}
+	for domain, source := range s.DisposableDomains {
+		v.addDisposableDomain(domain, source)
+		for _, also := range s.DisposableAlsoIn[domain] {
+			v.addDisposableDomain(domain, also)
+		}
+	}
+	v.bloomFilter, v.bloomSalted = nil, nil
+	if len(s.BloomFilters) > 0 {
+		v.bloomFilter, v.bloomSalted = s.BloomFilters[0], s.BloomFilters[1:]
+	}
+	v.bloomOptions = s.BloomOptions
+	v.disposableMX = setOf(s.DisposableMX)
+	v.disposableUpdated = s.DisposableUpdated
+
+	v.freeProviders = make(map[string]struct{}, len(s.FreeProviders))
+	v.freeProviderBases = make(map[string]struct{})
+	for _, provider := range s.FreeProviders {
+		v.addFreeProvider(provider)
+	}
+	v.freeProvidersUpdated = s.FreeProvidersUpdated
+
+	v.trustedDomains = setOf(s.TrustedDomains)
+	v.protectedDomains = s.ProtectedDomains
+	if v.protectedDomains == nil {
+		v.protectedDomains = make(map[string]string)
+	}
+	v.allowedDomains = allowed
+	v.spamtrapAddresses = spamtrapAddresses
+	v.spamtrapLocalParts = spamtrapLocalParts
+	v.bannedLocalParts = setOf(s.BannedLocalParts)
+	v.existingAddresses = s.ExistingAddresses
+	if len(s.TLDs) > 0 {
+		v.tlds = setOf(s.TLDs)
+	}
+	if len(s.MXProviders) > 0 {
+		v.mxProviders = s.MXProviders
+	}
+
+	for _, entry := range s.DNSCache {
+		v.dnsCache.Add(entry.Domain, DNSCacheEntry{
+			MX:            entry.MX,
+			Authenticated: entry.Authenticated,
+			CachedAt:      entry.CachedAt,
+		})
+	}
+
+	return nil
+}
+
+// compileSnapshotPatterns compiles the regular expressions saved in a snapshot
+func compileSnapshotPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern in snapshot %q: %v", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// patternStrings returns the source of compiled patterns
+func patternStrings(patterns []*regexp.Regexp) []string {
+	sources := make([]string, len(patterns))
+	for i, re := range patterns {
+		sources[i] = re.String()
+	}
+	return sources
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setOf returns a set containing keys
+func setOf(keys []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return set
+}
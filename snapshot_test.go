@@ -0,0 +1,109 @@
+package mailcop_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestSnapshot(t *testing.T) {
+	emptyList := filepath.Join(t.TempDir(), "empty.json")
+	require.NoError(t, os.WriteFile(emptyList, []byte(`[]`), 0644))
+
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.CheckDisposable = true
+	opts.CheckFreeProvider = true
+	opts.CheckConfusables = true
+	opts.AllowedDomainPatterns = []string{"*.org", "*.com", "*.test"}
+	opts.SpamtrapPatterns = []string{"abuse"}
+	opts.BannedLocalParts = []string{"test"}
+
+	sourceOpts := opts
+	sourceOpts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+	sourceOpts.Resolver = staticResolver{
+		"company.org": {"mx.company.org"},
+		"burner.test": {"mx.burner-mail.test"},
+	}
+
+	source, err := mailcop.New(sourceOpts)
+	require.NoError(t, err)
+	source.RegisterDisposableMXHosts([]string{"mx.burner-mail.test"})
+	source.RegisterFreeProviders([]string{"freemail.org"})
+	source.RegisterTrustedDomains([]string{"throwaway.com"})
+	source.RegisterProtectedDomains([]string{"paypal.com"})
+	source.RegisterExistingAddresses([]string{"jane@company.org"})
+	source.RegisterMXProviderPatterns(map[string][]string{"company": {"company.org"}})
+	require.True(t, source.Validate("user@company.org").IsValid)
+	require.True(t, source.Validate("user@burner.test").IsDisposable)
+
+	var buf bytes.Buffer
+	require.NoError(t, source.Snapshot(&buf))
+
+	// The target starts empty and can't resolve anything
+	targetOpts := opts
+	targetOpts.AllowedDomainPatterns = nil
+	targetOpts.SpamtrapPatterns = nil
+	targetOpts.BannedLocalParts = nil
+	targetOpts.DisposableDomainsURL = "file://" + emptyList
+	targetOpts.Resolver = staticResolver{}
+
+	target, err := mailcop.New(targetOpts)
+	require.NoError(t, err)
+	assert.False(t, target.Validate("user@company.org").IsValid)
+
+	require.NoError(t, target.Restore(&buf))
+
+	result := target.Validate("user@company.org")
+	assert.True(t, result.IsValid)
+	assert.True(t, result.DNSCacheHit)
+	assert.Equal(t, "company", result.MailProvider, "registered mail provider pattern")
+
+	result = target.Validate("user@tempmail.com")
+	assert.True(t, result.IsDisposable)
+	source1, _ := source.DisposableSource("tempmail.com")
+	source2, _ := target.DisposableSource("tempmail.com")
+	assert.Equal(t, source1, source2)
+
+	assert.False(t, target.Validate("user@throwaway.com").IsDisposable, "trusted domain")
+	assert.True(t, target.Validate("user@burner.test").IsDisposable, "disposable MX host")
+	assert.True(t, target.Validate("user@freemail.org").IsFreeProvider)
+	assert.True(t, target.Validate("user@gmail.com").IsFreeProvider)
+	assert.True(t, target.Validate("user@paypa1.com").IsConfusable)
+	assert.True(t, target.Validate("abuse@company.org").IsSpamtrap)
+	assert.ErrorIs(t, target.Validate("test@company.org").LastError, mailcop.ErrBannedLocalPart)
+	assert.True(t, target.Validate("jane+promo@company.org").IsDuplicate)
+	assert.ErrorIs(t, target.Validate("user@company.net").LastError, mailcop.ErrDomainNotAllowed)
+	assert.Equal(t, source.DisposableListLastUpdated().UTC(), target.DisposableListLastUpdated().UTC())
+
+	t.Run("bloom filter", func(t *testing.T) {
+		source, err := mailcop.New(sourceOpts)
+		require.NoError(t, err)
+		require.NoError(t, source.UseBloomFilter(sourceOpts.DisposableDomainsURL, mailcop.BloomOptions{
+			FalsePositiveRate:    0.01,
+			VerificationAttempts: 2,
+		}))
+
+		var buf bytes.Buffer
+		require.NoError(t, source.Snapshot(&buf))
+
+		target, err := mailcop.New(targetOpts)
+		require.NoError(t, err)
+		require.NoError(t, target.Restore(&buf))
+
+		assert.True(t, target.Validate("user@tempmail.com").IsDisposable)
+		assert.False(t, target.Validate("user@company.org").IsDisposable)
+	})
+
+	t.Run("invalid snapshots", func(t *testing.T) {
+		assert.Error(t, target.Restore(bytes.NewBufferString("not json")))
+		assert.Error(t, target.Restore(bytes.NewBufferString(`{"Version": 99}`)))
+		assert.True(t, target.Validate("user@tempmail.com").IsDisposable, "state is unchanged")
+	})
+}
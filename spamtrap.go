@@ -0,0 +1,78 @@
+package mailcop
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RegisterSpamtrapPatterns adds patterns of addresses known to be spamtraps. Patterns
+// without an "@" match the local part and patterns with one match the full address,
+// using the same glob and /regex/ syntax as AllowedDomainPatterns.
+func (v *Validator) RegisterSpamtrapPatterns(patterns []string) error {
+	var localParts, addresses []*regexp.Regexp
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(pattern, "@") {
+			addresses = append(addresses, re)
+		} else {
+			localParts = append(localParts, re)
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.spamtrapLocalParts = append(v.spamtrapLocalParts, localParts...)
+	v.spamtrapAddresses = append(v.spamtrapAddresses, addresses...)
+	return nil
+}
+
+// LoadSpamtrapPatterns loads spamtrap patterns from a JSON array in a file or URL
+func (v *Validator) LoadSpamtrapPatterns(urlStr string) error {
+	if urlStr == "" {
+		return nil
+	}
+
+	patterns, _, err := v.loadProviderList(urlStr)
+	if err != nil {
+		return fmt.Errorf("failed to load spamtrap patterns: %v", err)
+	}
+
+	if err := v.RegisterSpamtrapPatterns(patterns); err != nil {
+		return err
+	}
+
+	v.logger.Debug("list refreshed", "list", "spamtraps", "url", urlStr, "count", len(patterns))
+	return nil
+}
+
+// isSpamtrap checks if an address matches a spamtrap pattern. Matching is
+// case-insensitive against the local part or the full address.
+func (v *Validator) isSpamtrap(address string, at int) bool {
+	address = strings.ToLower(address)
+	localPart := address[:at]
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for _, re := range v.spamtrapLocalParts {
+		if re.MatchString(localPart) {
+			return true
+		}
+	}
+	for _, re := range v.spamtrapAddresses {
+		if re.MatchString(address) {
+			return true
+		}
+	}
+	return false
+}
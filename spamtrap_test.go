@@ -0,0 +1,52 @@
+package mailcop_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestSpamtraps(t *testing.T) {
+	listPath := filepath.Join(t.TempDir(), "spamtraps.json")
+	require.NoError(t, os.WriteFile(listPath, []byte(`["trap-*@company.org"]`), 0644))
+
+	opts := mailcop.DefaultOptions()
+	opts.SpamtrapPatterns = []string{"abuse", "spam?trap", `/^honeypot[0-9]+$/`}
+	opts.SpamtrapListURL = "file://" + listPath
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	require.NoError(t, v.RegisterSpamtrapPatterns([]string{"never-opted-in@example.org"}))
+
+	tests := []struct {
+		email        string
+		wantSpamtrap bool
+	}{
+		{email: "abuse@company.org", wantSpamtrap: true},
+		{email: "Abuse@Company.org", wantSpamtrap: true},
+		{email: "spam-trap@company.org", wantSpamtrap: true},
+		{email: "honeypot42@company.org", wantSpamtrap: true},
+		{email: "trap-1@company.org", wantSpamtrap: true},
+		{email: "never-opted-in@EXAMPLE.org", wantSpamtrap: true},
+		{email: "abuse-team@company.org", wantSpamtrap: false},
+		{email: "trap-1@other.org", wantSpamtrap: false},
+		{email: "never-opted-in@company.org", wantSpamtrap: false},
+		{email: "user@company.org", wantSpamtrap: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(tt.email)
+			assert.Equal(t, tt.wantSpamtrap, result.IsSpamtrap)
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		assert.Error(t, v.RegisterSpamtrapPatterns([]string{"/(/"}))
+	})
+}
@@ -0,0 +1,37 @@
+package mailcop
+
+import (
+	"errors"
+	"regexp"
+)
+
+// ErrDisplayNameSpoof is returned in ValidationResult.LastError when
+// RejectDisplayNameSpoof is set and the display name contains an email address at a
+// different domain, as in "billing@paypal.com" <scammer@evil.example>
+var ErrDisplayNameSpoof = errors.New("display name references a different domain")
+
+// displayNameEmail matches an email-like token in a display name and captures its domain
+var displayNameEmail = regexp.MustCompile(`[^\s<>"'@(),;:]+@((?:[\p{L}\p{N}](?:[\p{L}\p{N}-]*[\p{L}\p{N}])?\.)+\p{L}{2,})`)
+
+// displayNameSpoofDomain returns the domain of the first email-like token in a display
+// name that belongs to a different organization than domain, or "" if there's none.
+// Subdomains of the same registrable domain, like "paypal.com" and "mail.paypal.com",
+// aren't considered a mismatch.
+func displayNameSpoofDomain(name, domain string) string {
+	for _, match := range displayNameEmail.FindAllStringSubmatch(name, -1) {
+		if named := normalizeDomain(match[1]); !sameOrganization(named, domain) {
+			return named
+		}
+	}
+	return ""
+}
+
+// sameOrganization reports whether two domains are equal or share a registrable domain
+func sameOrganization(a, b string) bool {
+	if a == b {
+		return true
+	}
+	registrable, _ := registrableDomain(a)
+	other, _ := registrableDomain(b)
+	return registrable != "" && registrable == other
+}
@@ -0,0 +1,66 @@
+package mailcop
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of cumulative validation counters since the Validator was created.
+type Stats struct {
+	Validated             int64         // Total number of validations
+	Valid                 int64         // Validations that passed
+	Invalid               int64         // Validations that failed
+	DisposableHits        int64         // Validations where the domain was disposable
+	FreeProviderHits      int64         // Validations where the domain was a free provider
+	DNSCacheHits          int64         // MX lookups served from the DNS cache
+	DNSCacheMisses        int64         // MX lookups that queried the resolver
+	AverageValidationTime time.Duration // Mean ValidationTime across all validations
+}
+
+// stats holds the counters behind Stats. They're updated atomically so that
+// reading them never contends with validations.
+type stats struct {
+	validated        atomic.Int64
+	valid            atomic.Int64
+	invalid          atomic.Int64
+	disposableHits   atomic.Int64
+	freeProviderHits atomic.Int64
+	dnsCacheHits     atomic.Int64
+	dnsCacheMisses   atomic.Int64
+	validationTime   atomic.Int64 // Total validation time in nanoseconds
+}
+
+// Stats returns a snapshot of the validator's cumulative counters. It's cheap and
+// safe to call concurrently with validations, e.g. from a metrics endpoint.
+func (v *Validator) Stats() Stats {
+	s := Stats{
+		Validated:        v.stats.validated.Load(),
+		Valid:            v.stats.valid.Load(),
+		Invalid:          v.stats.invalid.Load(),
+		DisposableHits:   v.stats.disposableHits.Load(),
+		FreeProviderHits: v.stats.freeProviderHits.Load(),
+		DNSCacheHits:     v.stats.dnsCacheHits.Load(),
+		DNSCacheMisses:   v.stats.dnsCacheMisses.Load(),
+	}
+	if s.Validated > 0 {
+		s.AverageValidationTime = time.Duration(v.stats.validationTime.Load() / s.Validated)
+	}
+	return s
+}
+
+// record adds a finished validation to the counters
+func (s *stats) record(result ValidationResult) {
+	s.validated.Add(1)
+	if result.IsValid {
+		s.valid.Add(1)
+	} else {
+		s.invalid.Add(1)
+	}
+	if result.IsDisposable {
+		s.disposableHits.Add(1)
+	}
+	if result.IsFreeProvider {
+		s.freeProviderHits.Add(1)
+	}
+	s.validationTime.Add(int64(result.ValidationTime))
+}
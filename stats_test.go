@@ -0,0 +1,47 @@
+package mailcop_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestStats(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.CheckDisposable = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+	opts.CheckFreeProvider = true
+	opts.DNSCacheTTL = time.Hour
+	opts.Resolver = staticResolver{
+		"company.org": {"mx.company.org."},
+		"gmail.com":   {"gmail-smtp-in.l.google.com."},
+	}
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+	v.RegisterDisposableDomains([]string{"tempmail.com"})
+
+	assert.Equal(t, mailcop.Stats{}, v.Stats())
+
+	v.Validate("user@company.org")
+	v.Validate("other@company.org")
+	v.Validate("user@gmail.com")
+	v.Validate("user@tempmail.com")
+	v.Validate("invalid@")
+
+	stats := v.Stats()
+	assert.Equal(t, int64(5), stats.Validated)
+	assert.Equal(t, int64(3), stats.Valid)
+	assert.Equal(t, int64(2), stats.Invalid, "tempmail.com has no MX records and invalid@ doesn't parse")
+	assert.Equal(t, int64(1), stats.DisposableHits)
+	assert.Equal(t, int64(1), stats.FreeProviderHits)
+	assert.Equal(t, int64(1), stats.DNSCacheHits)
+	assert.Equal(t, int64(3), stats.DNSCacheMisses)
+	assert.Positive(t, stats.AverageValidationTime)
+}
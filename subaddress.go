@@ -0,0 +1,15 @@
+package mailcop
+
+import "strings"
+
+// stripSubaddress removes a "+tag" subaddress from the local part of an address whose
+// "@" is at index at, returning the base address, the tag without its "+" and the
+// index of the "@" in the base address. Local parts that are only a tag, like "+news",
+// are left alone.
+func stripSubaddress(address string, at int) (string, string, int) {
+	base, tag, ok := strings.Cut(address[:at], "+")
+	if !ok || base == "" {
+		return address, "", at
+	}
+	return base + address[at:], tag, len(base)
+}
@@ -0,0 +1,58 @@
+package mailcop
+
+import "strings"
+
+// suffixTrie stores domains by their labels from right to left, so every listed parent
+// of a domain is found in a single walk instead of one map lookup per parent
+type suffixTrie struct {
+	children map[string]*suffixTrie // Child nodes keyed by the next label to the left
+	terminal bool                   // Whether the labels up to this node form a listed domain
+}
+
+func newSuffixTrie() *suffixTrie {
+	return &suffixTrie{}
+}
+
+// insert adds a domain to the trie
+func (t *suffixTrie) insert(domain string) {
+	node := t
+	for end := len(domain); end >= 0; {
+		i := strings.LastIndexByte(domain[:end], '.')
+		label := domain[i+1 : end]
+
+		child, ok := node.children[label]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[string]*suffixTrie)
+			}
+			child = &suffixTrie{}
+			node.children[label] = child
+		}
+		node = child
+		end = i
+	}
+	node.terminal = true
+}
+
+// match returns the most specific listed domain that is the domain itself or one of
+// its parents
+func (t *suffixTrie) match(domain string) (string, bool) {
+	var matched string
+	var found bool
+
+	node := t
+	for end := len(domain); end >= 0; {
+		i := strings.LastIndexByte(domain[:end], '.')
+		child, ok := node.children[domain[i+1:end]]
+		if !ok {
+			break
+		}
+		node = child
+		if node.terminal {
+			matched, found = domain[i+1:], true
+		}
+		end = i
+	}
+
+	return matched, found
+}
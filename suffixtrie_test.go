@@ -0,0 +1,122 @@
+package mailcop
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuffixTrie(t *testing.T) {
+	trie := newSuffixTrie()
+	for _, domain := range []string{"tempmail.com", "mail.burner.io", "spam.mail.burner.io"} {
+		trie.insert(domain)
+	}
+
+	tests := []struct {
+		domain      string
+		wantMatched string
+	}{
+		{domain: "tempmail.com", wantMatched: "tempmail.com"},
+		{domain: "inbox.tempmail.com", wantMatched: "tempmail.com"},
+		{domain: "a.b.tempmail.com", wantMatched: "tempmail.com"},
+		{domain: "mail.burner.io", wantMatched: "mail.burner.io"},
+		{domain: "spam.mail.burner.io", wantMatched: "spam.mail.burner.io"},
+		{domain: "x.spam.mail.burner.io", wantMatched: "spam.mail.burner.io"},
+		{domain: "burner.io"},
+		{domain: "nottempmail.com"},
+		{domain: "com"},
+		{domain: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			matched, ok := trie.match(tt.domain)
+			assert.Equal(t, tt.wantMatched != "", ok)
+			assert.Equal(t, tt.wantMatched, matched)
+		})
+	}
+}
+
+// matchParents is the naive subdomain match the suffix trie replaces: one map lookup
+// for the domain and each of its parents
+func matchParents(domains map[string]struct{}, domain string) bool {
+	for {
+		if _, ok := domains[domain]; ok {
+			return true
+		}
+		i := strings.IndexByte(domain, '.')
+		if i < 0 {
+			return false
+		}
+		domain = domain[i+1:]
+	}
+}
+
+func BenchmarkSubdomainMatching(b *testing.B) {
+	const listSize = 100_000
+
+	domains := make([]string, listSize)
+	for i := range domains {
+		domains[i] = fmt.Sprintf("disposable-%d.com", i)
+	}
+
+	// Mostly misses with a few levels of subdomains, like real signups
+	lookups := []string{
+		"mail.eu.company.co.uk",
+		"user.inbox.disposable-42.com",
+		"gmail.com",
+		"a.b.c.d.example.org",
+	}
+
+	tests := []struct {
+		name  string
+		setup func() func(domain string) bool
+	}{
+		{
+			name: "Parent walk",
+			setup: func() func(string) bool {
+				set := make(map[string]struct{}, len(domains))
+				for _, domain := range domains {
+					set[domain] = struct{}{}
+				}
+				return func(domain string) bool { return matchParents(set, domain) }
+			},
+		},
+		{
+			name: "Suffix trie",
+			setup: func() func(string) bool {
+				trie := newSuffixTrie()
+				for _, domain := range domains {
+					trie.insert(domain)
+				}
+				return func(domain string) bool {
+					_, ok := trie.match(domain)
+					return ok
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			runtime.GC()
+			var m1 runtime.MemStats
+			runtime.ReadMemStats(&m1)
+
+			match := tt.setup()
+
+			runtime.GC()
+			var m2 runtime.MemStats
+			runtime.ReadMemStats(&m2)
+			b.ReportMetric(float64(m2.Alloc-m1.Alloc), "struct_bytes")
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				match(lookups[i%len(lookups)])
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package mailcop
+
+// Summary is a rollup of a batch of validation results, e.g. from ValidateMany or
+// ValidateFile. Flag counts include valid results, so a disposable domain is counted
+// whether or not RejectDisposable was set.
+type Summary struct {
+	Total        int            // Number of results
+	Valid        int            // Results that passed
+	Invalid      int            // Results that failed
+	Disposable   int            // Results with a disposable domain
+	FreeProvider int            // Results with a free provider domain
+	Reserved     int            // Results with a reserved domain
+	IPDomain     int            // Results with an IP address domain
+	Reasons      map[Reason]int // Failed results by Reason
+}
+
+// Summarize counts valid, invalid and flagged results and groups failures by Reason
+func Summarize(results []ValidationResult) Summary {
+	summary := Summary{
+		Total:   len(results),
+		Reasons: make(map[Reason]int),
+	}
+
+	for _, result := range results {
+		if result.IsValid {
+			summary.Valid++
+		} else {
+			summary.Invalid++
+			summary.Reasons[result.Reason]++
+		}
+
+		if result.IsDisposable {
+			summary.Disposable++
+		}
+		if result.IsFreeProvider {
+			summary.FreeProvider++
+		}
+		if result.IsReserved {
+			summary.Reserved++
+		}
+		if result.IsIPDomain {
+			summary.IPDomain++
+		}
+	}
+
+	return summary
+}
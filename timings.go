@@ -0,0 +1,26 @@
+package mailcop
+
+import "time"
+
+// Timings breaks a result's ValidationTime down by phase, to show whether list lookups
+// or DNS dominate when deciding whether to enable the bloom filter or the DNS cache.
+// Custom rules aren't counted in any phase, so the phases can add up to less than
+// ValidationTime.
+type Timings struct {
+	Parse time.Duration // Parsing and the address checks that run before the domain checks
+	Lists time.Duration // Domain checks that don't need the network, like the TLD, disposable and free provider lists
+	DNS   time.Duration // MX lookup and the DNS checks that depend on it, plus reverse DNS for IP domains
+}
+
+// usesDNS reports whether a check can query the resolver
+func (c CheckType) usesDNS() bool {
+	return c == CheckTypeMX || c == CheckTypeIPDomain
+}
+
+// stopTimers sets a result's ValidationTime and the time its list checks took, where
+// start is when validation began and listStart is when the domain checks began
+func stopTimers(result *ValidationResult, start, listStart time.Time) {
+	now := time.Now()
+	result.ValidationTime = now.Sub(start)
+	result.Timings.Lists = now.Sub(listStart) - result.Timings.DNS
+}
@@ -0,0 +1,48 @@
+package mailcop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patrickward/mailcop"
+)
+
+func TestTimings(t *testing.T) {
+	opts := mailcop.DefaultOptions()
+	opts.CheckDNS = true
+	opts.Resolver = slowResolver{delay: 20 * time.Millisecond}
+	opts.MinDomainLength = 3
+
+	v, err := mailcop.New(opts)
+	require.NoError(t, err)
+
+	t.Run("DNS dominates a slow lookup", func(t *testing.T) {
+		result := v.Validate("user@company.org")
+		require.True(t, result.IsValid, result.LastError)
+
+		timings := result.Timings
+		assert.GreaterOrEqual(t, timings.DNS, 20*time.Millisecond)
+		assert.Greater(t, timings.DNS, timings.Lists)
+		assert.LessOrEqual(t, timings.Parse+timings.Lists+timings.DNS, result.ValidationTime)
+	})
+
+	t.Run("domain checks stop before DNS", func(t *testing.T) {
+		result := v.Validate("user@ab")
+		require.False(t, result.IsValid)
+
+		assert.Zero(t, result.Timings.DNS)
+		assert.LessOrEqual(t, result.Timings.Parse+result.Timings.Lists, result.ValidationTime)
+	})
+
+	t.Run("parse failures are all parse time", func(t *testing.T) {
+		result := v.Validate("not an email")
+		require.False(t, result.IsValid)
+
+		assert.Equal(t, result.ValidationTime, result.Timings.Parse)
+		assert.Zero(t, result.Timings.Lists)
+		assert.Zero(t, result.Timings.DNS)
+	})
+}
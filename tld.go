@@ -0,0 +1,107 @@
+package mailcop
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// defaultTLDList is the bundled list of top-level domains from the IANA root zone
+//
+//go:embed data/tlds.txt
+var defaultTLDList string
+
+// registrableDomain returns the domain registered under a domain's public suffix
+// (eTLD+1), so "mail.example.co.uk" returns "example.co.uk". It also reports whether
+// the domain is itself a public suffix, such as "co.uk" or "github.io", in which case
+// there's no registrable domain. Single-label domains like "localhost" are public
+// suffixes by the list's default rule.
+func registrableDomain(domain string) (string, bool) {
+	if suffix, _ := publicsuffix.PublicSuffix(domain); suffix == domain {
+		return "", true
+	}
+
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	if err != nil {
+		return "", false
+	}
+	return registrable, false
+}
+
+// validateTLD checks that a domain has a top-level domain when required and that
+// the TLD meets the minimum length. IP address domains are not checked.
+func (v *Validator) validateTLD(domain string) error {
+	idx := strings.LastIndex(domain, ".")
+	if idx == -1 {
+		if v.options.RequireTLD {
+			return fmt.Errorf("domain must include a top-level domain")
+		}
+		return nil
+	}
+
+	tld := domain[idx+1:]
+	if tld == "" && v.options.RequireTLD {
+		return fmt.Errorf("top-level domain must not be empty")
+	}
+
+	if v.options.MinTLDLength > 0 && len(tld) < v.options.MinTLDLength {
+		return fmt.Errorf("top-level domain must be at least %d characters", v.options.MinTLDLength)
+	}
+
+	return nil
+}
+
+// LoadTLDs replaces the known top-level domains with a list loaded from a file or
+// URL. The list uses the IANA format: one TLD per line, with "#" comments.
+func (v *Validator) LoadTLDs(urlStr string) error {
+	if urlStr == "" {
+		return nil
+	}
+
+	start := time.Now()
+	data, _, err := v.readListSource(urlStr)
+	if err != nil {
+		v.logger.Debug("list fetch failed", "url", urlStr, "error", err, "duration", time.Since(start))
+		return fmt.Errorf("failed to load TLD list: %v", err)
+	}
+
+	tlds := parseTLDList(string(data))
+	v.logger.Debug("list fetched", "url", urlStr, "count", len(tlds), "duration", time.Since(start))
+	if len(tlds) == 0 {
+		return fmt.Errorf("failed to load TLD list: no TLDs found")
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.tlds = tlds
+	v.logger.Debug("list refreshed", "list", "tlds", "url", urlStr, "count", len(tlds))
+	return nil
+}
+
+// parseTLDList parses a newline-delimited TLD list, skipping comments and blank lines
+func parseTLDList(data string) map[string]struct{} {
+	tlds := make(map[string]struct{})
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tlds[strings.ToLower(line)] = struct{}{}
+	}
+	return tlds
+}
+
+// isKnownTLD checks if the domain ends in a top-level domain from the loaded list
+func (v *Validator) isKnownTLD(domain string) bool {
+	tld := strings.ToLower(domain[strings.LastIndex(domain, ".")+1:])
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	_, ok := v.tlds[tld]
+	return ok
+}
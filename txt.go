@@ -0,0 +1,77 @@
+package mailcop
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// txtCacheEntry is a cached TXT lookup result
+type txtCacheEntry struct {
+	records  []string
+	err      error
+	cachedAt time.Time
+}
+
+// lookupTXT returns the (possibly cached) TXT records of a name. Results expire after
+// DNSCacheTTL, or DNSNegativeCacheTTL for failed lookups, which dnsTTL reads under the
+// lock since SetDNSCacheTTL can change them. The validator's resolver must be a
+// TXTResolver, which New enforces for the options that need it.
+func (v *Validator) lookupTXT(name string) ([]string, error) {
+	key := v.dnsCacheKey(name)
+	if cached, ok := v.txtCache.Get(key); ok {
+		if time.Since(cached.cachedAt) < v.dnsTTL(DNSCacheEntry{Err: cached.err}) {
+			return cached.records, cached.err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.options.DNSTimeout)
+	defer cancel()
+
+	start := time.Now()
+	records, err := v.resolver.(TXTResolver).LookupTXT(ctx, name)
+
+	attrs := []any{"name", name, "type", "TXT", "latency", time.Since(start)}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	v.logger.Debug("DNS lookup", attrs...)
+
+	v.txtCache.Add(key, txtCacheEntry{records: records, err: err, cachedAt: time.Now()})
+	return records, err
+}
+
+// hasTXTVersion reports whether a name has a TXT record tagged with version, such as
+// "v=spf1", compared case-insensitively. Failed lookups report false.
+func (v *Validator) hasTXTVersion(name, version string) bool {
+	records, err := v.lookupTXT(name)
+	if err != nil {
+		return false
+	}
+	for _, record := range records {
+		record = strings.TrimSpace(record)
+		if len(record) < len(version) || !strings.EqualFold(record[:len(version)], version) {
+			continue
+		}
+		// The version must be a whole term, so "v=spf10" isn't an SPF record
+		if rest := record[len(version):]; rest == "" || rest[0] == ' ' || rest[0] == ';' {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSPF reports whether a domain publishes an SPF record. The record isn't evaluated.
+func (v *Validator) hasSPF(domain string) bool {
+	return v.hasTXTVersion(domain, "v=spf1")
+}
+
+// hasDMARC reports whether a domain publishes a DMARC record. The policy isn't evaluated.
+func (v *Validator) hasDMARC(domain string) bool {
+	return v.hasTXTVersion("_dmarc."+domain, "v=DMARC1")
+}
+
+// ErrNoDMARC is returned in ValidationResult.LastError when RejectNoDMARC is set and the
+// domain doesn't publish a DMARC record
+var ErrNoDMARC = errors.New("domain has no DMARC record")
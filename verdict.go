@@ -0,0 +1,65 @@
+package mailcop
+
+// domainVerdictCacheSize is the number of domains whose verdicts are memoized when
+// CacheDomainVerdicts is enabled
+const domainVerdictCacheSize = 1024
+
+// domainVerdict holds the list-based verdicts for a domain. MX results are cached
+// separately in the DNS cache.
+type domainVerdict struct {
+	ipDomain          bool
+	reserved          bool
+	disposable        bool
+	disposableSource  string
+	disposableCount   int // Number of sources listing the domain
+	freeProvider      bool
+	publicSuffix      bool   // Whether the domain is exactly a public suffix
+	registrableDomain string // eTLD+1 of the domain, empty for IP domains and public suffixes
+	generation        uint64 // Value of verdictGeneration when the verdict was computed
+}
+
+// verdictFor returns the IP, reserved, public suffix, disposable and free provider
+// verdicts for a lowercased domain, memoized when CacheDomainVerdicts is enabled
+func (v *Validator) verdictFor(domain string) domainVerdict {
+	if v.verdicts == nil {
+		return v.computeVerdict(domain, 0)
+	}
+
+	generation := v.verdictGeneration.Load()
+	if cached, ok := v.verdicts.Get(domain); ok && cached.generation == generation {
+		return cached
+	}
+
+	verdict := v.computeVerdict(domain, generation)
+	v.verdicts.Add(domain, verdict)
+	return verdict
+}
+
+// computeVerdict runs the list-based checks for a domain
+func (v *Validator) computeVerdict(domain string, generation uint64) domainVerdict {
+	verdict := domainVerdict{
+		ipDomain:     v.isIPDomain(domain),
+		reserved:     v.isReserved(domain),
+		freeProvider: v.isFreeProvider(domain),
+		generation:   generation,
+	}
+
+	if !verdict.ipDomain {
+		verdict.registrableDomain, verdict.publicSuffix = registrableDomain(domain)
+	}
+
+	var listed string
+	listed, verdict.disposable = v.matchDisposable(domain)
+	if verdict.disposable {
+		verdict.disposableSource, _ = v.DisposableSource(listed)
+		verdict.disposableCount = v.disposableSourceCount(listed)
+	}
+	return verdict
+}
+
+// invalidateVerdicts marks every cached verdict as stale. It's called whenever a
+// domain list changes. Verdicts computed while a list was changing carry the old
+// generation, so they're never served afterwards.
+func (v *Validator) invalidateVerdicts() {
+	v.verdictGeneration.Add(1)
+}
@@ -0,0 +1,51 @@
+package mailcop
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheDomainVerdicts(t *testing.T) {
+	opts := DefaultOptions()
+	opts.CacheDomainVerdicts = true
+	opts.CheckDisposable = true
+	opts.CheckFreeProvider = true
+	opts.DisposableDomainsURL = "file://" + filepath.Join("testdata", "domains.json")
+
+	v, err := New(opts)
+	require.NoError(t, err)
+
+	result := v.Validate("user@gmail.com")
+	assert.True(t, result.IsFreeProvider)
+	assert.Equal(t, 1, v.verdicts.Len())
+
+	// Repeated domains are served from the cache
+	v.Validate("other@GMAIL.com")
+	assert.Equal(t, 1, v.verdicts.Len())
+
+	t.Run("list changes invalidate verdicts", func(t *testing.T) {
+		assert.False(t, v.Validate("user@burner.io").IsDisposable)
+
+		v.RegisterDisposableDomains([]string{"burner.io"})
+		result := v.Validate("user@burner.io")
+		assert.True(t, result.IsDisposable)
+		assert.Equal(t, "registered", result.DisposableSource)
+
+		v.RegisterTrustedDomains([]string{"burner.io"})
+		assert.False(t, v.Validate("user@burner.io").IsDisposable)
+
+		assert.False(t, v.Validate("user@newmail.org").IsFreeProvider)
+		v.RegisterFreeProviders([]string{"newmail.org"})
+		assert.True(t, v.Validate("user@newmail.org").IsFreeProvider)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		v, err := New(DefaultOptions())
+		require.NoError(t, err)
+		assert.Nil(t, v.verdicts)
+		assert.True(t, v.Validate("user@company.org").IsValid)
+	})
+}